@@ -21,3 +21,13 @@ func GetAWSAccountID(conf aws.Config) (string, error) {
 	}
 	return *result.Account, nil
 }
+
+// getCallerARN returns the ARN of the caller
+func getCallerARN(ctx context.Context, conf aws.Config) (string, error) {
+	svc := sts.NewFromConfig(conf)
+	result, err := svc.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(result.Arn), nil
+}
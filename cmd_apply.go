@@ -67,6 +67,7 @@ var cmdApply = &runnerImpl{
 				ruleNames = append(ruleNames, r.Name)
 			}
 		}
+		c.warnScheduleTimezones(ruleNames)
 
 		if *parallel < 1 {
 			return errors.New("-parallel must be at least 1")
@@ -92,9 +93,7 @@ var cmdApply = &runnerImpl{
 				if err := ru.applyInternal(ctx, a.AwsConf, true, format); err != nil {
 					return applyDryRunResult{}, err
 				}
-				for _, v := range ru.ContainerOverrides {
-					v.Environment = nil
-				}
+				ru.hideEnvironment()
 				bs, _ := yaml.Marshal(ru)
 				return applyDryRunResult{ruleName: ruleName, ruleYaml: string(bs)}, nil
 			}
@@ -116,19 +115,24 @@ var cmdApply = &runnerImpl{
 				if err := ru.applyInternal(ctx, a.AwsConf, false, format); err != nil {
 					return err
 				}
-				for _, v := range ru.ContainerOverrides {
-					v.Environment = nil
-				}
+				ru.hideEnvironment()
 				bs, _ := yaml.Marshal(ru)
 				log.Printf("✅ following rule applied\n%s", string(bs))
 			}
 		}
 
 		if *prune {
-			orphanedRules, err := extractOrphanedRules(ctx, a.AwsConf, c.BaseConfig, ruleNames)
+			orphanedRules, err := extractOrphanedRules(ctx, a.AwsConf, c.BaseConfig, c.ruleARNs(ruleNames))
 			if err != nil {
 				return err
 			}
+			if c.usesScheduler() {
+				orphanedSchedules, err := extractOrphanedSchedules(ctx, a.AwsConf, c.BaseConfig, c.scheduleNames(ruleNames))
+				if err != nil {
+					return err
+				}
+				orphanedRules = append(orphanedRules, orphanedSchedules...)
+			}
 
 			if len(orphanedRules) > 0 {
 				log.Printf("orphaned rules will be deleted %s", dryRunSuffix)
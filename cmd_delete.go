@@ -0,0 +1,198 @@
+package ecschedule
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchevents"
+	cweTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchevents/types"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	schedulerTypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+)
+
+var cmdDelete = &runnerImpl{
+	name:        "delete",
+	description: "delete the rule",
+	run: func(ctx context.Context, argv []string, outStream, errStream io.Writer) error {
+		fs := flag.NewFlagSet("ecschedule delete", flag.ContinueOnError)
+		fs.SetOutput(errStream)
+		var (
+			conf    = fs.String("conf", "", "configuration")
+			rule    = fs.String("rule", "", "rule")
+			dryRun  = fs.Bool("dry-run", false, "dry run")
+			yes     = fs.Bool("y", false, "delete without confirmation")
+			force   = fs.Bool("force", false, "delete the rule even if it is not tagged with the tracking id of the configuration")
+			unified = fs.Bool("u", false, "output diff in unified format (colored, similar to git diff)")
+			noColor = fs.Bool("no-color", false, "disable colored output (Unified diff format only)")
+		)
+		if err := fs.Parse(argv); err != nil {
+			return err
+		}
+		setupColor(*noColor)
+		if *rule == "" {
+			return errors.New("-rule option required")
+		}
+		a := getApp(ctx)
+		c, err := loadConfigForState(ctx, *conf)
+		if err != nil {
+			return err
+		}
+
+		// the rule may have been removed from the configuration already
+		base := c.BaseConfig
+		if cr := c.GetRuleByName(*rule); cr != nil {
+			base = cr.BaseConfig
+		}
+		ru, err := remoteRule(ctx, a.AwsConf, base, *rule)
+		if err != nil {
+			return err
+		}
+		if err := verifyTrackingID(ru, base.TrackingID, *force, func() (string, error) {
+			return ru.remoteTrackingID(ctx, a.AwsConf)
+		}); err != nil {
+			return err
+		}
+
+		if !*dryRun && !*yes {
+			ok, err := confirm(os.Stdin, errStream, fmt.Sprintf("delete the rule %q?", *rule))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errors.New("canceled")
+			}
+		}
+		if err := ru.deleteInternal(ctx, a.AwsConf, *dryRun, selectDiffFormat(*unified)); err != nil {
+			return err
+		}
+		var dryRunSuffix string
+		if *dryRun {
+			dryRunSuffix = " (dry-run)"
+		}
+		log.Printf("✅ deleted the rule %q%s", *rule, dryRunSuffix)
+		return nil
+	},
+}
+
+// remoteRule returns the remote rule, or the remote schedule with the scheduler backend
+func remoteRule(ctx context.Context, awsConf aws.Config, bc *BaseConfig, name string) (*Rule, error) {
+	var (
+		ru  *Rule
+		err error
+	)
+	if (&Rule{BaseConfig: bc}).useScheduler() {
+		svc := scheduler.NewFromConfig(awsConf, func(o *scheduler.Options) {
+			o.Region = bc.Region
+		})
+		group := (&Rule{BaseConfig: bc}).scheduleGroup()
+		ru, err = newRuleFromRemoteSchedule(ctx, svc, bc, group, name)
+		var nfe *schedulerTypes.ResourceNotFoundException
+		if errors.As(err, &nfe) {
+			return nil, fmt.Errorf("schedule %q not found in the schedule group %q", name, group)
+		}
+	} else {
+		ru, err = NewRuleFromRemote(ctx, awsConf, bc, name)
+		var nfe *cweTypes.ResourceNotFoundException
+		if errors.As(err, &nfe) {
+			return nil, fmt.Errorf("rule %q not found", name)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ru == nil {
+		return nil, fmt.Errorf("rule %q does not run tasks on the cluster %q", name, bc.Cluster)
+	}
+	return ru, nil
+}
+
+// verifyTrackingID checks that the remote rule ru is tagged with the tracking id want of the
+// configuration, so that rules managed by another configuration are not deleted by mistake. lookup
+// returns the tracking id of the remote rule. Schedules in the default group are not checked with
+// a warning, since the group can't be tagged.
+func verifyTrackingID(ru *Rule, want string, force bool, lookup func() (string, error)) error {
+	if ru.useScheduler() && ru.scheduleGroup() == defaultScheduleGroup {
+		log.Printf("⚠️  the tracking id of the schedule %q is not checked, since the schedule group %q can't be tagged", ru.Name, defaultScheduleGroup)
+		return nil
+	}
+	got, err := lookup()
+	if err != nil {
+		return err
+	}
+	if got == want {
+		return nil
+	}
+	tagged := fmt.Sprintf("tagged with the tracking id %q", got)
+	if got == "" {
+		tagged = "not tagged with any tracking id"
+	}
+	if !force {
+		return fmt.Errorf("rule %q is %s, not %q of the configuration. use -force to delete it anyway", ru.Name, tagged, want)
+	}
+	log.Printf("⚠️  deleting the rule %q %s, not %q", ru.Name, tagged, want)
+	return nil
+}
+
+// remoteTrackingID returns the tracking ID tagged on the remote rule. Schedules can't be tagged,
+// so the tag of the schedule group is returned with the scheduler backend.
+func (r *Rule) remoteTrackingID(ctx context.Context, awsConf aws.Config) (string, error) {
+	var tags map[string]string
+	if r.useScheduler() {
+		svc := scheduler.NewFromConfig(awsConf, func(o *scheduler.Options) {
+			o.Region = r.Region
+		})
+		g, err := svc.GetScheduleGroup(ctx, &scheduler.GetScheduleGroupInput{
+			Name: aws.String(r.scheduleGroup()),
+		})
+		if err != nil {
+			return "", err
+		}
+		out, err := svc.ListTagsForResource(ctx, &scheduler.ListTagsForResourceInput{
+			ResourceArn: g.Arn,
+		})
+		if err != nil {
+			return "", err
+		}
+		tags = map[string]string{}
+		for _, tag := range out.Tags {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	} else {
+		svc := cloudwatchevents.NewFromConfig(awsConf, func(o *cloudwatchevents.Options) {
+			o.Region = r.Region
+		})
+		out, err := svc.ListTagsForResource(ctx, &cloudwatchevents.ListTagsForResourceInput{
+			ResourceARN: aws.String(r.ruleARN()),
+		})
+		if err != nil {
+			return "", err
+		}
+		tags = map[string]string{}
+		for _, tag := range out.Tags {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+	return tags[trackingIDTagKey], nil
+}
+
+// confirm asks the question on out and reports whether the answer read from in is yes
+func confirm(in io.Reader, out io.Writer, question string) (bool, error) {
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
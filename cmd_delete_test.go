@@ -0,0 +1,105 @@
+package ecschedule
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConfirm(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{" y \n", true},
+		{"y", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+		{"yep\n", false},
+	}
+	for _, tc := range testCases {
+		var out bytes.Buffer
+		got, err := confirm(strings.NewReader(tc.input), &out, `delete the rule "foo"?`)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %s", tc.input, err)
+		}
+		if got != tc.want {
+			t.Errorf("%q: got %t, want %t", tc.input, got, tc.want)
+		}
+		if g, e := out.String(), `delete the rule "foo"? [y/N]: `; g != e {
+			t.Errorf("%q: prompt: got %q, want %q", tc.input, g, e)
+		}
+	}
+}
+
+func TestVerifyTrackingID(t *testing.T) {
+	events := &Rule{Name: "hoge", BaseConfig: &BaseConfig{TrackingID: "api"}}
+	testCases := []struct {
+		name   string
+		rule   *Rule
+		remote string
+		force  bool
+		expect string
+	}{{
+		name:   "tracked",
+		rule:   events,
+		remote: "api",
+	}, {
+		name:   "another tracking id",
+		rule:   events,
+		remote: "batch",
+		expect: `rule "hoge" is tagged with the tracking id "batch", not "api" of the configuration. use -force to delete it anyway`,
+	}, {
+		name:   "untagged",
+		rule:   events,
+		expect: `rule "hoge" is not tagged with any tracking id, not "api" of the configuration. use -force to delete it anyway`,
+	}, {
+		name:   "forced",
+		rule:   events,
+		remote: "batch",
+		force:  true,
+	}, {
+		name:   "schedule group",
+		rule:   &Rule{Name: "hoge", BaseConfig: &BaseConfig{TrackingID: "api", Backend: backendScheduler, ScheduleGroup: "api"}},
+		remote: "batch",
+		expect: `rule "hoge" is tagged with the tracking id "batch", not "api" of the configuration. use -force to delete it anyway`,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := verifyTrackingID(tc.rule, "api", tc.force, func() (string, error) {
+				return tc.remote, nil
+			})
+			if tc.expect == "" {
+				if err != nil {
+					t.Errorf("error should be nil, but: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expect {
+				t.Errorf("error should be %q, but: %v", tc.expect, err)
+			}
+		})
+	}
+
+	t.Run("default schedule group", func(t *testing.T) {
+		ru := &Rule{Name: "hoge", BaseConfig: &BaseConfig{TrackingID: "api", Backend: backendScheduler}}
+		err := verifyTrackingID(ru, "api", false, func() (string, error) {
+			t.Error("the tracking id of the default group should not be looked up")
+			return "", nil
+		})
+		if err != nil {
+			t.Errorf("error should be nil, but: %s", err)
+		}
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		want := errors.New("access denied")
+		if err := verifyTrackingID(events, "api", true, func() (string, error) { return "", want }); !errors.Is(err, want) {
+			t.Errorf("error should be %q, but: %v", want, err)
+		}
+	})
+}
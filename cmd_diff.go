@@ -9,8 +9,10 @@ import (
 	"log"
 	"os"
 	"sync/atomic"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchevents"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
 	"github.com/goccy/go-yaml"
 )
 
@@ -35,12 +37,17 @@ var cmdDiff = &runnerImpl{
 			prune    = fs.Bool("prune", false, "detect orphaned rules for deletion")
 			validate = fs.Bool("validate", false, "perform validation (env, tfstate, ssm, task definition)")
 			parallel = fs.Int("parallel", 1, "number of parallel workers (default: 1, recommended: 1-10 due to AWS API rate limits. Note: output order is not guaranteed when parallel > 1)")
+			tz       = fs.String("explain-timezone", "", "IANA timezone to show the schedule expressions in as well (e.g. Asia/Tokyo)")
 		)
 		if err := fs.Parse(argv); err != nil {
 			return err
 		}
 
 		setupColor(*noColor)
+		eo, err := newExplainOptions(*tz, time.Now())
+		if err != nil {
+			return err
+		}
 
 		if !*all && *rule == "" {
 			return errors.New("-rule or -all option required")
@@ -76,6 +83,7 @@ var cmdDiff = &runnerImpl{
 				ruleNames = append(ruleNames, r.Name)
 			}
 		}
+		c.warnScheduleTimezones(ruleNames)
 
 		format := selectDiffFormat(*unified)
 
@@ -83,6 +91,9 @@ var cmdDiff = &runnerImpl{
 		svc := cloudwatchevents.NewFromConfig(a.AwsConf, func(o *cloudwatchevents.Options) {
 			o.Region = c.Region
 		})
+		schedulerSvc := scheduler.NewFromConfig(a.AwsConf, func(o *scheduler.Options) {
+			o.Region = c.Region
+		})
 
 		var hasValidationError atomic.Bool
 
@@ -113,12 +124,18 @@ var cmdDiff = &runnerImpl{
 				}
 			}
 
-			from, to, err := ru.diff(ctx, svc)
+			var from, to string
+			var err error
+			if ru.useScheduler() {
+				from, to, err = ru.scheduleDiff(ctx, schedulerSvc)
+			} else {
+				from, to, err = ru.diff(ctx, svc)
+			}
 			if err != nil {
 				return result, err
 			}
 
-			result.diffOutput = formatDiff(ruleName, from, to, format)
+			result.diffOutput = formatDiff(ruleName, from, to, format, eo)
 			return result, nil
 		}
 
@@ -148,10 +165,17 @@ var cmdDiff = &runnerImpl{
 
 		// Display orphaned rules if -prune is specified
 		if *prune {
-			orphanedRules, err := extractOrphanedRules(ctx, a.AwsConf, c.BaseConfig, ruleNames)
+			orphanedRules, err := extractOrphanedRules(ctx, a.AwsConf, c.BaseConfig, c.ruleARNs(ruleNames))
 			if err != nil {
 				return err
 			}
+			if c.usesScheduler() {
+				orphanedSchedules, err := extractOrphanedSchedules(ctx, a.AwsConf, c.BaseConfig, c.scheduleNames(ruleNames))
+				if err != nil {
+					return err
+				}
+				orphanedRules = append(orphanedRules, orphanedSchedules...)
+			}
 
 			for _, rule := range orphanedRules {
 				remoteRuleYaml, err := yaml.Marshal(rule)
@@ -159,7 +183,7 @@ var cmdDiff = &runnerImpl{
 					return err
 				}
 
-				diffOutput := formatDiff(rule.Name, string(remoteRuleYaml), "", format)
+				diffOutput := formatDiff(rule.Name, string(remoteRuleYaml), "", format, eo)
 
 				if *unified {
 					fmt.Fprintln(errStream, diffOutput)
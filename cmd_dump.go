@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchevents"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchevents/types"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
 	"github.com/goccy/go-yaml"
 )
 
@@ -24,10 +26,18 @@ var cmdDump = &runnerImpl{
 			region  = fs.String("region", "", "region")
 			cluster = fs.String("cluster", "", "cluster")
 			role    = fs.String("role", "", "role")
+			backend = fs.String("backend", "", "backend to dump: events or scheduler")
+			group   = fs.String("schedule-group", "", "schedule group to dump (scheduler backend only)")
+			bus     = fs.String("event-bus-name", "", "event bus to dump (events backend only)")
+			tz      = fs.String("explain-timezone", "", "IANA timezone to show the schedule expressions in as well (e.g. Asia/Tokyo)")
 		)
 		if err := fs.Parse(argv); err != nil {
 			return err
 		}
+		eo, err := newExplainOptions(*tz, time.Now())
+		if err != nil {
+			return err
+		}
 		a := getApp(ctx)
 		c := a.Config
 		accountID := a.AccountID
@@ -65,56 +75,129 @@ var cmdDump = &runnerImpl{
 		}
 		c.Region = *region
 		c.Cluster = *cluster
-
-		var (
-			awsConf = a.AwsConf
-			svc     = cloudwatchevents.NewFromConfig(awsConf, func(o *cloudwatchevents.Options) {
-				o.Region = aws.ToString(region)
-			})
-			remoteRules []types.Rule
-			nextToken   *string
-		)
-		for {
-			r, err := svc.ListRules(ctx, &cloudwatchevents.ListRulesInput{
-				NextToken: nextToken,
-			})
-			if err != nil {
-				return err
-			}
-			remoteRules = append(remoteRules, r.Rules...)
-			if r.NextToken == nil {
-				break
-			}
-			nextToken = r.NextToken
+		if *backend == "" {
+			*backend = c.Backend
+		}
+		if *group == "" {
+			*group = c.ScheduleGroup
+		}
+		if *bus == "" {
+			*bus = c.EventBusName
 		}
 
-		var (
-			rules         []*Rule
-			roleArnPrefix = fmt.Sprintf("arn:aws:iam::%s:role/", accountID)
-			rg            = &ruleGetter{
-				svc:              svc,
-				ruleArnPrefix:    fmt.Sprintf("arn:aws:events:%s:%s:rule/", *region, accountID),
-				clusterArn:       fmt.Sprintf("arn:aws:ecs:%s:%s:cluster/%s", *region, accountID, *cluster),
-				taskDefArnPrefix: fmt.Sprintf("arn:aws:ecs:%s:%s:task-definition/", *region, accountID),
-				roleArnPrefix:    roleArnPrefix,
-				sqsArnPrefix:     fmt.Sprintf("arn:aws:sqs:%s:%s:", *region, accountID),
+		var rules []*Rule
+		switch *backend {
+		case "", backendEvents:
+			rules, err = dumpRules(ctx, a.AwsConf, accountID, *region, *cluster, *bus)
+			if *bus != defaultEventBusName {
+				c.EventBusName = *bus
 			}
-		)
-		for _, r := range remoteRules {
-			ru, err := rg.getRule(ctx, &r)
-			if err != nil {
-				return err
+		case backendScheduler:
+			if *group == "" {
+				*group = defaultScheduleGroup
 			}
-			if ru != nil {
-				rules = append(rules, ru)
+			c.AccountID = accountID
+			rules, err = dumpSchedules(ctx, a.AwsConf, c.BaseConfig, *group)
+			c.Backend = backendScheduler
+			if *group != defaultScheduleGroup {
+				c.ScheduleGroup = *group
 			}
+		default:
+			return fmt.Errorf("unknown backend %q", *backend)
+		}
+		if err != nil {
+			return err
 		}
 		c.Rules = rules
+		// the remote expressions are in UTC already
+		c.ScheduleTimezone = ""
 		bs, err := yaml.Marshal(c)
 		if err != nil {
 			return err
 		}
-		fmt.Fprint(outStream, string(bs))
+		fmt.Fprint(outStream, annotateSchedules(string(bs), eo))
 		return nil
 	},
 }
+
+func dumpRules(ctx context.Context, awsConf aws.Config, accountID, region, cluster, eventBusName string) ([]*Rule, error) {
+	var (
+		svc = cloudwatchevents.NewFromConfig(awsConf, func(o *cloudwatchevents.Options) {
+			o.Region = region
+		})
+		remoteRules []types.Rule
+		nextToken   *string
+		bus         *string
+	)
+	if eventBusName != "" {
+		bus = aws.String(eventBusName)
+	}
+	for {
+		r, err := svc.ListRules(ctx, &cloudwatchevents.ListRulesInput{
+			EventBusName: bus,
+			NextToken:    nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		remoteRules = append(remoteRules, r.Rules...)
+		if r.NextToken == nil {
+			break
+		}
+		nextToken = r.NextToken
+	}
+
+	var (
+		rules         []*Rule
+		roleArnPrefix = fmt.Sprintf("arn:aws:iam::%s:role/", accountID)
+		rg            = &ruleGetter{
+			svc:              svc,
+			ruleArnPrefix:    ruleArnPrefix(region, accountID, eventBusName),
+			clusterArn:       fmt.Sprintf("arn:aws:ecs:%s:%s:cluster/%s", region, accountID, cluster),
+			taskDefArnPrefix: fmt.Sprintf("arn:aws:ecs:%s:%s:task-definition/", region, accountID),
+			roleArnPrefix:    roleArnPrefix,
+			sqsArnPrefix:     fmt.Sprintf("arn:aws:sqs:%s:%s:", region, accountID),
+		}
+	)
+	for _, r := range remoteRules {
+		ru, err := rg.getRule(ctx, &r)
+		if err != nil {
+			return nil, err
+		}
+		if ru != nil {
+			rules = append(rules, ru)
+		}
+	}
+	return rules, nil
+}
+
+func dumpSchedules(ctx context.Context, awsConf aws.Config, bc *BaseConfig, group string) ([]*Rule, error) {
+	svc := scheduler.NewFromConfig(awsConf, func(o *scheduler.Options) {
+		o.Region = bc.Region
+	})
+	names, err := listScheduleNames(ctx, svc, group)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		rules []*Rule
+		rg    = newScheduleGetter(bc)
+	)
+	for _, name := range names {
+		s, err := svc.GetSchedule(ctx, &scheduler.GetScheduleInput{
+			Name:      aws.String(name),
+			GroupName: aws.String(group),
+		})
+		if err != nil {
+			return nil, err
+		}
+		ru, err := rg.getSchedule(s)
+		if err != nil {
+			return nil, err
+		}
+		if ru != nil {
+			rules = append(rules, ru)
+		}
+	}
+	return rules, nil
+}
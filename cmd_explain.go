@@ -0,0 +1,83 @@
+package ecschedule
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+var cmdExplain = &runnerImpl{
+	name:        "explain",
+	description: "explain the schedule expressions in English",
+	offline:     true,
+	run: func(ctx context.Context, argv []string, outStream, errStream io.Writer) error {
+		fs := flag.NewFlagSet("ecschedule explain", flag.ContinueOnError)
+		fs.SetOutput(errStream)
+		fs.Usage = func() {
+			fmt.Fprintln(fs.Output(), "Usage: ecschedule explain [options] [EXPRESSION ...]")
+			fmt.Fprintln(fs.Output(), "Explains the given expressions, or the rules of the configuration without them.")
+			fs.PrintDefaults()
+		}
+		var (
+			conf   = fs.String("conf", "", "configuration")
+			rule   = fs.String("rule", "", "rules to explain (comma separated, default: all rules)")
+			filter = fs.String("filter", "", "regexp to filter the rules to explain by name")
+			tz     = fs.String("timezone", "", "IANA timezone to show the schedules in as well (e.g. Asia/Tokyo)")
+		)
+		if err := fs.Parse(argv); err != nil {
+			return err
+		}
+		eo, err := newExplainOptions(*tz, time.Now())
+		if err != nil {
+			return err
+		}
+		if fs.NArg() > 0 {
+			for _, expr := range fs.Args() {
+				exp, err := explainSchedule(expr, "", eo.loc, eo.now)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(outStream, "%s: %s\n", expr, exp)
+			}
+			return nil
+		}
+
+		a := getApp(ctx)
+		c := a.Config
+		if *conf != "" {
+			f, err := os.Open(*conf)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			c, err = LoadConfig(ctx, f, a.AccountID, *conf, a.loadConfigOptions()...)
+			if err != nil {
+				return err
+			}
+		}
+		if c == nil {
+			return errors.New("-conf option or expressions required")
+		}
+		ruleNames, err := selectRuleNames(c, *rule, *rule == "", *filter)
+		if err != nil {
+			return err
+		}
+		for _, name := range ruleNames {
+			ru := c.GetRuleByName(name)
+			if ru.ScheduleExpression == "" {
+				fmt.Fprintf(outStream, "%s: triggered by the event pattern\n", name)
+				continue
+			}
+			exp, err := explainSchedule(ru.ScheduleExpression, ru.ScheduleExpressionTimezone, eo.loc, eo.now)
+			if err != nil {
+				return fmt.Errorf("rule %q: %w", name, err)
+			}
+			fmt.Fprintf(outStream, "%s: %s: %s\n", name, ru.ScheduleExpression, exp)
+		}
+		return nil
+	},
+}
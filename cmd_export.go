@@ -0,0 +1,84 @@
+package ecschedule
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+var cmdExport = &runnerImpl{
+	name:        "export",
+	description: "export the schedules of the rules",
+	run: func(ctx context.Context, argv []string, outStream, errStream io.Writer) error {
+		fs := flag.NewFlagSet("ecschedule export", flag.ContinueOnError)
+		fs.SetOutput(errStream)
+		var (
+			conf   = fs.String("conf", "", "configuration")
+			format = fs.String("format", "ics", "export format: ics")
+			rule   = fs.String("rule", "", "rules to export (comma separated, default: all rules)")
+			filter = fs.String("filter", "", "regexp to filter the rules to export by name")
+			from   = fs.String("from", "", "start of the events in RFC3339 (default: now)")
+			to     = fs.String("to", "", "end of the occurrences of the schedules which can't recur in RFC3339 (default: 30 days after -from)")
+		)
+		if err := fs.Parse(argv); err != nil {
+			return err
+		}
+		if *format != "ics" {
+			return fmt.Errorf("unknown format %q: ics expected", *format)
+		}
+		now := time.Now()
+		start := now
+		if *from != "" {
+			t, err := time.Parse(time.RFC3339, *from)
+			if err != nil {
+				return fmt.Errorf("invalid -from: %w", err)
+			}
+			start = t
+		}
+		end := start.AddDate(0, 0, 30)
+		if *to != "" {
+			t, err := time.Parse(time.RFC3339, *to)
+			if err != nil {
+				return fmt.Errorf("invalid -to: %w", err)
+			}
+			end = t
+		}
+		if !start.Before(end) {
+			return errors.New("-to must be after -from")
+		}
+		a := getApp(ctx)
+		c := a.Config
+		if *conf != "" {
+			f, err := os.Open(*conf)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			c, err = LoadConfig(ctx, f, a.AccountID, *conf, a.loadConfigOptions()...)
+			if err != nil {
+				return err
+			}
+		}
+		if c == nil {
+			return errors.New("-conf option required")
+		}
+		ruleNames, err := selectRuleNames(c, *rule, *rule == "", *filter)
+		if err != nil {
+			return err
+		}
+		var rules []*Rule
+		for _, name := range ruleNames {
+			rules = append(rules, c.GetRuleByName(name))
+		}
+		out, err := exportICS(c.Cluster, rules, now, start, end)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(outStream, out)
+		return err
+	},
+}
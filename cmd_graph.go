@@ -0,0 +1,109 @@
+package ecschedule
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var cmdGraph = &runnerImpl{
+	name:        "graph",
+	description: "render the timeline of the schedules",
+	offline:     true,
+	run: func(ctx context.Context, argv []string, outStream, errStream io.Writer) error {
+		fs := flag.NewFlagSet("ecschedule graph", flag.ContinueOnError)
+		fs.SetOutput(errStream)
+		var (
+			conf   = fs.String("conf", "", "configuration")
+			format = fs.String("format", "mermaid", "output format: mermaid, svg or html")
+			rule   = fs.String("rule", "", "rules to render (comma separated, default: all rules)")
+			filter = fs.String("filter", "", "regexp to filter the rules to render by name")
+			from   = fs.String("from", "", "start of the timeline in RFC3339 (default: today 00:00 UTC)")
+			span   = fs.String("span", "24h", "length of the timeline, e.g. 24h or 7d")
+		)
+		if err := fs.Parse(argv); err != nil {
+			return err
+		}
+		switch *format {
+		case "mermaid", "svg", "html":
+		default:
+			return fmt.Errorf("unknown format %q: mermaid, svg or html expected", *format)
+		}
+		start := time.Now().UTC().Truncate(24 * time.Hour)
+		if *from != "" {
+			t, err := time.Parse(time.RFC3339, *from)
+			if err != nil {
+				return fmt.Errorf("invalid -from: %w", err)
+			}
+			start = t
+		}
+		d, err := parseSpan(*span)
+		if err != nil {
+			return err
+		}
+		a := getApp(ctx)
+		c := a.Config
+		if *conf != "" {
+			f, err := os.Open(*conf)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			c, err = LoadConfig(ctx, f, a.AccountID, *conf, a.loadConfigOptions()...)
+			if err != nil {
+				return err
+			}
+		}
+		if c == nil {
+			return errors.New("-conf option required")
+		}
+		ruleNames, err := selectRuleNames(c, *rule, *rule == "", *filter)
+		if err != nil {
+			return err
+		}
+		var rules []*Rule
+		for _, name := range ruleNames {
+			rules = append(rules, c.GetRuleByName(name))
+		}
+		g, err := newGraph("ecschedule: "+c.Cluster, rules, start, start.Add(d))
+		if err != nil {
+			return err
+		}
+		var out string
+		switch *format {
+		case "mermaid":
+			out = g.mermaid()
+		case "svg":
+			out = g.svg()
+		case "html":
+			out = g.html()
+		}
+		_, err = io.WriteString(outStream, out)
+		return err
+	},
+}
+
+// parseSpan parses a duration which accepts days as well, e.g. 7d
+func parseSpan(s string) (time.Duration, error) {
+	var (
+		d   time.Duration
+		err error
+	)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid -span %q: positive duration such as 24h or 7d expected", s)
+	}
+	return d, nil
+}
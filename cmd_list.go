@@ -0,0 +1,259 @@
+package ecschedule
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// sync states of the rules shown by list
+const (
+	ruleInSync   = "in-sync"
+	ruleDrifted  = "drifted"
+	ruleMissing  = "missing"
+	ruleOrphaned = "orphaned"
+)
+
+type ruleStatus struct {
+	Name               string      `yaml:"name" json:"name"`
+	Status             string      `yaml:"status" json:"status"`
+	Enabled            bool        `yaml:"enabled" json:"enabled"`
+	TaskDefinition     string      `yaml:"taskDefinition,omitempty" json:"taskDefinition,omitempty"`
+	ScheduleExpression string      `yaml:"scheduleExpression,omitempty" json:"scheduleExpression,omitempty"`
+	NextFireTimes      []time.Time `yaml:"nextFireTimes,omitempty" json:"nextFireTimes,omitempty"`
+}
+
+var (
+	cmdList   = newListCommand("list", "list the rules with the remote state and the next fire times")
+	cmdStatus = newListCommand("status", "alias of list")
+)
+
+func newListCommand(name, description string) *runnerImpl {
+	return &runnerImpl{
+		name:        name,
+		description: description,
+		run: func(ctx context.Context, argv []string, outStream, errStream io.Writer) error {
+			fs := flag.NewFlagSet("ecschedule "+name, flag.ContinueOnError)
+			fs.SetOutput(errStream)
+			var (
+				conf   = fs.String("conf", "", "configuration")
+				rule   = fs.String("rule", "", "rules to list (comma separated). orphaned rules are not listed with it")
+				filter = fs.String("filter", "", "regexp to filter the rules to list by name")
+				next   = fs.Int("next", 3, "number of the next fire times to show")
+				format = fs.String("format", "table", "output format: table, json or yaml")
+			)
+			if err := fs.Parse(argv); err != nil {
+				return err
+			}
+			switch *format {
+			case "table", "json", "yaml":
+			default:
+				return fmt.Errorf("unknown format %q: table, json or yaml expected", *format)
+			}
+			if *next < 0 {
+				return errors.New("-next must not be negative")
+			}
+			a := getApp(ctx)
+			c := a.Config
+			if *conf != "" {
+				f, err := os.Open(*conf)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				c, err = LoadConfig(ctx, f, a.AccountID, *conf, a.loadConfigOptions()...)
+				if err != nil {
+					return err
+				}
+			}
+			if c == nil {
+				return errors.New("-conf option required")
+			}
+			ruleNames, err := selectRuleNames(c, *rule, *rule == "", *filter)
+			if err != nil {
+				return err
+			}
+
+			// fetch the remote rules in bulk for each event bus or schedule group
+			remotes := map[string]map[string]*Rule{}
+			for _, name := range ruleNames {
+				ru := c.GetRuleByName(name)
+				key := remoteRulesKey(ru)
+				if _, ok := remotes[key]; ok {
+					continue
+				}
+				var rules []*Rule
+				if ru.useScheduler() {
+					rules, err = dumpSchedules(ctx, a.AwsConf, ru.BaseConfig, ru.scheduleGroup())
+				} else {
+					rules, err = dumpRules(ctx, a.AwsConf, ru.AccountID, ru.Region, ru.Cluster, ru.EventBusName)
+				}
+				if err != nil {
+					return err
+				}
+				remotes[key] = map[string]*Rule{}
+				for _, r := range rules {
+					remotes[key][r.Name] = r
+				}
+			}
+
+			now := time.Now()
+			var statuses []*ruleStatus
+			for _, name := range ruleNames {
+				ru := c.GetRuleByName(name)
+				st, err := newRuleStatus(ru, remotes[remoteRulesKey(ru)][name], now, *next)
+				if err != nil {
+					return err
+				}
+				statuses = append(statuses, st)
+			}
+
+			if *rule == "" {
+				var allNames []string
+				for _, r := range c.Rules {
+					allNames = append(allNames, r.Name)
+				}
+				orphaned, err := extractOrphanedRules(ctx, a.AwsConf, c.BaseConfig, c.ruleARNs(allNames))
+				if err != nil {
+					return err
+				}
+				if c.usesScheduler() {
+					orphanedSchedules, err := extractOrphanedSchedules(ctx, a.AwsConf, c.BaseConfig, c.scheduleNames(allNames))
+					if err != nil {
+						return err
+					}
+					orphaned = append(orphaned, orphanedSchedules...)
+				}
+				var reg *regexp.Regexp
+				if *filter != "" {
+					// already validated by selectRuleNames
+					reg = regexp.MustCompile(*filter)
+				}
+				for _, r := range orphaned {
+					if reg != nil && !reg.MatchString(r.Name) {
+						continue
+					}
+					st, err := newRuleStatus(nil, r, now, *next)
+					if err != nil {
+						return err
+					}
+					statuses = append(statuses, st)
+				}
+			}
+
+			switch *format {
+			case "json":
+				enc := json.NewEncoder(outStream)
+				enc.SetIndent("", "  ")
+				return enc.Encode(statuses)
+			case "yaml":
+				bs, err := yaml.Marshal(statuses)
+				if err != nil {
+					return err
+				}
+				_, err = outStream.Write(bs)
+				return err
+			}
+			fmt.Fprint(outStream, formatRuleStatuses(statuses))
+			return nil
+		},
+	}
+}
+
+// remoteRulesKey identifies the set of the remote rules fetched at once for the rule
+func remoteRulesKey(r *Rule) string {
+	if r.useScheduler() {
+		return strings.Join([]string{backendScheduler, r.Region, r.Cluster, r.scheduleGroup()}, "/")
+	}
+	return strings.Join([]string{backendEvents, r.Region, r.Cluster, r.EventBusName}, "/")
+}
+
+// newRuleStatus compares the local rule with the remote one. Either of them may be nil.
+// The enabled flag and the fire times follow the remote rule when it exists, since they
+// are what actually happens.
+func newRuleStatus(local, remote *Rule, now time.Time, n int) (*ruleStatus, error) {
+	var st *ruleStatus
+	switch {
+	case local == nil:
+		st = &ruleStatus{Name: remote.Name, Status: ruleOrphaned}
+	case remote == nil:
+		st = &ruleStatus{Name: local.Name, Status: ruleMissing}
+	default:
+		st = &ruleStatus{Name: local.Name, Status: ruleInSync}
+		to, err := local.localYAMLForDiff()
+		if err != nil {
+			return nil, err
+		}
+		from, err := yaml.Marshal(remote)
+		if err != nil {
+			return nil, err
+		}
+		if string(from) != to {
+			st.Status = ruleDrifted
+		}
+	}
+	actual := remote
+	if actual == nil {
+		actual = local
+	}
+	described := local
+	if described == nil {
+		described = remote
+	}
+	var taskDefs []string
+	for _, ta := range described.targets() {
+		taskDefs = append(taskDefs, ta.TaskDefinition)
+	}
+	st.TaskDefinition = strings.Join(taskDefs, ",")
+	st.Enabled = !actual.Disabled
+	st.ScheduleExpression = actual.ScheduleExpression
+	if !st.Enabled || n == 0 {
+		return st, nil
+	}
+	s, err := actual.schedule()
+	if err != nil {
+		return nil, err
+	}
+	if s != nil {
+		st.NextFireTimes = nextFireTimes(s, now, n)
+	}
+	return st, nil
+}
+
+func formatRuleStatuses(statuses []*ruleStatus) string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tSTATE\tTASK DEFINITION\tSCHEDULE\tNEXT")
+	for _, st := range statuses {
+		state := "enabled"
+		if !st.Enabled {
+			state = "disabled"
+		}
+		schedule := st.ScheduleExpression
+		if schedule == "" {
+			schedule = "-"
+		}
+		next := "-"
+		if len(st.NextFireTimes) > 0 {
+			var times []string
+			for _, t := range st.NextFireTimes {
+				times = append(times, t.Format(time.RFC3339))
+			}
+			next = strings.Join(times, ", ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", st.Name, st.Status, state, st.TaskDefinition, schedule, next)
+	}
+	w.Flush()
+	return buf.String()
+}
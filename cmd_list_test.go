@@ -0,0 +1,105 @@
+package ecschedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewRuleStatus(t *testing.T) {
+	now := mustTime(t, "2026-01-01T00:00:00Z")
+	newRule := func(expr, taskDef string, disabled bool) *Rule {
+		return &Rule{
+			Name:               "hoge",
+			ScheduleExpression: expr,
+			Disabled:           disabled,
+			Target:             &Target{TaskDefinition: taskDef, Role: defaultRole},
+		}
+	}
+	testCases := []struct {
+		name          string
+		local, remote *Rule
+		status        string
+		enabled       bool
+		taskDef       string
+		next          string
+	}{
+		{
+			name:    "in sync",
+			local:   newRule("cron(0 9 * * ? *)", "hoge:1", false),
+			remote:  newRule("cron(0 9 * * ? *)", "hoge:1", false),
+			status:  ruleInSync,
+			enabled: true,
+			taskDef: "hoge:1",
+			next:    "2026-01-01T09:00:00Z",
+		},
+		{
+			name:    "drifted fires by the remote schedule",
+			local:   newRule("cron(0 9 * * ? *)", "hoge:2", false),
+			remote:  newRule("cron(0 3 * * ? *)", "hoge:1", false),
+			status:  ruleDrifted,
+			enabled: true,
+			taskDef: "hoge:2",
+			next:    "2026-01-01T03:00:00Z",
+		},
+		{
+			name:    "missing",
+			local:   newRule("rate(1 hour)", "hoge:1", false),
+			status:  ruleMissing,
+			enabled: true,
+			taskDef: "hoge:1",
+			next:    "2026-01-01T00:00:00Z",
+		},
+		{
+			name:    "orphaned and disabled",
+			remote:  newRule("cron(0 9 * * ? *)", "fuga:3", true),
+			status:  ruleOrphaned,
+			enabled: false,
+			taskDef: "fuga:3",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			st, err := newRuleStatus(tc.local, tc.remote, now, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if st.Status != tc.status {
+				t.Errorf("status: got %q, want %q", st.Status, tc.status)
+			}
+			if st.Enabled != tc.enabled {
+				t.Errorf("enabled: got %t, want %t", st.Enabled, tc.enabled)
+			}
+			if st.TaskDefinition != tc.taskDef {
+				t.Errorf("task definition: got %q, want %q", st.TaskDefinition, tc.taskDef)
+			}
+			if tc.next == "" {
+				if len(st.NextFireTimes) != 0 {
+					t.Errorf("no fire times expected, got %v", st.NextFireTimes)
+				}
+				return
+			}
+			if len(st.NextFireTimes) != 2 {
+				t.Fatalf("2 fire times expected, got %v", st.NextFireTimes)
+			}
+			if got := formatTimes(st.NextFireTimes)[0]; got != tc.next {
+				t.Errorf("next: got %q, want %q", got, tc.next)
+			}
+		})
+	}
+}
+
+func TestFormatRuleStatuses(t *testing.T) {
+	statuses := []*ruleStatus{
+		{Name: "hoge", Status: ruleInSync, Enabled: true, TaskDefinition: "hoge:1", ScheduleExpression: "cron(0 9 * * ? *)",
+			NextFireTimes: []time.Time{mustTime(t, "2026-01-01T09:00:00Z"), mustTime(t, "2026-01-02T09:00:00Z")}},
+		{Name: "fuga", Status: ruleOrphaned, TaskDefinition: "fuga:3"},
+	}
+	got := formatRuleStatuses(statuses)
+	want := `NAME  STATUS    STATE     TASK DEFINITION  SCHEDULE           NEXT
+hoge  in-sync   enabled   hoge:1           cron(0 9 * * ? *)  2026-01-01T09:00:00Z, 2026-01-02T09:00:00Z
+fuga  orphaned  disabled  fuga:3           -                  -
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,135 @@
+package ecschedule
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+var cmdMigrateToScheduler = &runnerImpl{
+	name:        "migrate-to-scheduler",
+	description: "migrate the rules to EventBridge Scheduler",
+	run: func(ctx context.Context, argv []string, outStream, errStream io.Writer) error {
+		fs := flag.NewFlagSet("ecschedule migrate-to-scheduler", flag.ContinueOnError)
+		fs.SetOutput(errStream)
+		var (
+			conf       = fs.String("conf", "", "configuration")
+			rule       = fs.String("rule", "", "rule")
+			all        = fs.Bool("all", false, "migrate all rules tracked by the tracking id")
+			trackingID = fs.String("tracking-id", "", "tracking id of the rules to migrate (default: trackingId of the configuration)")
+			group      = fs.String("schedule-group", "", "schedule group to create the schedules in (default: scheduleGroup of the configuration or \"default\")")
+			role       = fs.String("role", "", "role of the schedules, which must trust scheduler.amazonaws.com (default: role of the rules)")
+			deleteRule = fs.Bool("delete", false, "delete the rules after migration instead of disabling them")
+			dryRun     = fs.Bool("dry-run", false, "dry run")
+			unified    = fs.Bool("u", false, "output diff in unified format (colored, similar to git diff)")
+			noColor    = fs.Bool("no-color", false, "disable colored output (Unified diff format only)")
+		)
+		if err := fs.Parse(argv); err != nil {
+			return err
+		}
+
+		setupColor(*noColor)
+
+		if !*all && *rule == "" {
+			return errors.New("-rule or -all option required")
+		}
+		a := getApp(ctx)
+		c := a.Config
+		if *conf != "" {
+			f, err := os.Open(*conf)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			c, err = LoadConfig(ctx, f, a.AccountID, *conf, a.loadConfigOptions()...)
+			if err != nil {
+				return err
+			}
+		}
+		if c == nil {
+			return errors.New("-conf option required")
+		}
+		base := *c.BaseConfig
+		base.Backend = backendEvents
+		if *trackingID != "" {
+			base.TrackingID = *trackingID
+		}
+		if *group == "" {
+			*group = c.ScheduleGroup
+			if *group == "" {
+				*group = defaultScheduleGroup
+			}
+		}
+
+		// only the rules tagged with the tracking id are migrated
+		trackedRuleARNs, err := listTrackedRules(ctx, a.AwsConf, base.TrackingID)
+		if err != nil {
+			return err
+		}
+		var ruleARNs []string
+		if !*all {
+			arn := ruleArnPrefix(base.Region, base.AccountID, base.EventBusName) + *rule
+			if !slices.Contains(trackedRuleARNs, arn) {
+				return fmt.Errorf("rule %q is not tracked by the tracking id %q", *rule, base.TrackingID)
+			}
+			ruleARNs = append(ruleARNs, arn)
+		} else {
+			ruleARNs = trackedRuleARNs
+		}
+
+		type migration struct {
+			rule, schedule *Rule
+		}
+		var (
+			migrations []migration
+			errs       []string
+		)
+		for _, arn := range ruleARNs {
+			eventBusName, name := parseRuleARN(arn)
+			bc := base
+			bc.EventBusName = eventBusName
+			ru, err := NewRuleFromRemote(ctx, a.AwsConf, &bc, name)
+			if err != nil {
+				return err
+			}
+			if ru == nil {
+				log.Printf("skip migrating the rule %q, which does not run tasks on the cluster %q", name, base.Cluster)
+				continue
+			}
+			ru.carryScheduleTimezone(c.GetRuleByName(name))
+			sr := ru.schedulerRule(*group, *role)
+			if err := ru.validateMigration(sr); err != nil {
+				errs = append(errs, fmt.Sprintf("\trule %q: %s", name, err))
+				continue
+			}
+			migrations = append(migrations, migration{rule: ru, schedule: sr})
+		}
+		// validate all rules before migrating any of them
+		if len(errs) > 0 {
+			return fmt.Errorf("migration validation errors:\n%s", strings.Join(errs, "\n"))
+		}
+
+		var dryRunSuffix string
+		if *dryRun {
+			dryRunSuffix = " (dry-run)"
+		}
+		format := selectDiffFormat(*unified)
+		for _, m := range migrations {
+			if err := m.rule.migrateToScheduler(ctx, a.AwsConf, m.schedule, *deleteRule, *dryRun, format); err != nil {
+				return err
+			}
+			log.Printf("✅ migrated the rule %q%s", m.rule.Name, dryRunSuffix)
+		}
+		if len(migrations) > 0 && !*dryRun {
+			log.Printf("💡 set `backend: %s` in the configuration to manage the schedules", backendScheduler)
+		}
+		return nil
+	},
+}
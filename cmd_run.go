@@ -1,6 +1,7 @@
 package ecschedule
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"flag"
@@ -8,8 +9,18 @@ import (
 	"io"
 	"log"
 	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
 )
 
+type runResult struct {
+	ruleName string
+	taskArns []string
+	err      error
+	skipped  bool
+}
+
 var cmdRun = &runnerImpl{
 	name:        "run",
 	description: "run the rule",
@@ -17,16 +28,49 @@ var cmdRun = &runnerImpl{
 		fs := flag.NewFlagSet("ecschedule run", flag.ContinueOnError)
 		fs.SetOutput(errStream)
 		var (
-			conf   = fs.String("conf", "", "configuration")
-			rule   = fs.String("rule", "", "rule")
-			dryRun = fs.Bool("dry-run", false, "dry run")
-			// noWait = fs.Bool("no-wait", false, "exit immediately after starting the rule")
+			conf        = fs.String("conf", "", "configuration")
+			rule        = fs.String("rule", "", "rule (comma separated rules are run in the order)")
+			all         = fs.Bool("all", false, "run all rules")
+			filter      = fs.String("filter", "", "regexp to filter the rules to run by name")
+			dryRun      = fs.Bool("dry-run", false, "dry run")
+			noWait      = fs.Bool("no-wait", false, "exit immediately after starting the rule")
+			follow      = fs.Bool("follow", false, "stream the logs of the tasks until they stop")
+			timeout     = fs.Duration("timeout", 0, "timeout for waiting the tasks to stop (e.g. 30m, default: no timeout)")
+			taskDef     = fs.String("task-definition", "", "task definition to run instead of the rule's one (family:revision)")
+			count       = fs.Int("count", 0, "number of tasks to run instead of the rule's taskCount")
+			ovFile      = fs.String("overrides-file", "", "JSON file of the task overrides in the format of RunTask")
+			parallel    = fs.Int("parallel", 1, "number of rules to run in parallel (default: 1, recommended: 1-10 due to AWS API rate limits)")
+			sequential  = fs.Bool("sequential", false, "run the rules one by one in the order and stop at the first failure")
+			concurrency = fs.String("concurrency", concurrencyAllow, "policy when tasks of the rule are still running: allow, forbid or wait")
+			env         = newContainerEnvFlag()
+			command     = newContainerCommandFlag()
 		)
+		fs.Var(env, "env", "override an environment variable (CONTAINER.KEY=VALUE, repeatable)")
+		fs.Var(command, "command", "override the command of a container (CONTAINER='COMMAND', repeatable)")
 		if err := fs.Parse(argv); err != nil {
 			return err
 		}
-		if *rule == "" {
-			return errors.New("-rule option required")
+		if !*all && *rule == "" {
+			return errors.New("-rule or -all option required")
+		}
+		if *follow && *noWait {
+			return errors.New("-follow and -no-wait can't be specified at the same time")
+		}
+		if *parallel < 1 {
+			return errors.New("-parallel must be at least 1")
+		}
+		if err := validateConcurrency(*concurrency); err != nil {
+			return err
+		}
+		if *sequential && *parallel > 1 {
+			return errors.New("-sequential and -parallel can't be specified at the same time")
+		}
+		if *sequential && *noWait {
+			return errors.New("-sequential waits for the tasks of each rule to stop and can't be used with -no-wait")
+		}
+		overrides, err := newRunOverrides(*taskDef, *count, *ovFile, env, command)
+		if err != nil {
+			return err
 		}
 		a := getApp(ctx)
 		c := a.Config
@@ -44,23 +88,173 @@ var cmdRun = &runnerImpl{
 		if c == nil {
 			return errors.New("-conf option required")
 		}
-		ru := c.GetRuleByName(*rule)
-		if ru == nil {
-			return fmt.Errorf("no rules found for %s", *rule)
+		ruleNames, err := selectRuleNames(c, *rule, *all, *filter)
+		if err != nil {
+			return err
 		}
 		var dryRunSuffix string
 		if *dryRun {
 			dryRunSuffix = " (dry-run)"
 		}
-		log.Printf("running the rule %q%s", *rule, dryRunSuffix)
-		defer func() {
+		if *timeout > 0 && !*noWait {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *timeout)
+			defer cancel()
+		}
+		opts := &RunOptions{
+			NoWait:      *noWait,
+			Follow:      *follow,
+			DryRun:      *dryRun,
+			Overrides:   overrides,
+			Concurrency: *concurrency,
+			Out:         outStream,
+		}
+		processRunJob := func(ctx context.Context, ruleName string) (runResult, error) {
+			ru := c.GetRuleByName(ruleName)
+			log.Printf("running the rule %q%s", ruleName, dryRunSuffix)
+			arns, err := ru.runTasks(ctx, a.AwsConf, opts)
 			if err == nil {
-				log.Printf("✅ ran the rule %q%s", ru.Name, dryRunSuffix)
+				log.Printf("✅ ran the rule %q%s", ruleName, dryRunSuffix)
+			} else if len(ruleNames) > 1 {
+				log.Printf("❌ failed to run the rule %q: %s", ruleName, err)
 			}
-		}()
-		if *dryRun {
-			return nil
+			// failures are collected into the summary not to cancel the other rules
+			return runResult{ruleName: ruleName, taskArns: arns, err: err}, nil
+		}
+
+		if len(ruleNames) == 1 {
+			res, _ := processRunJob(ctx, ruleNames[0])
+			return res.err
+		}
+
+		byName := map[string]runResult{}
+		if *sequential {
+			var failed bool
+			for _, name := range ruleNames {
+				if failed {
+					byName[name] = runResult{ruleName: name, skipped: true}
+					continue
+				}
+				res, _ := processRunJob(ctx, name)
+				byName[name] = res
+				failed = res.err != nil
+			}
+		} else {
+			results, errChan := executeJobsInParallel[runResult](ctx, ruleNames, *parallel, processRunJob)
+			for res := range results {
+				byName[res.ruleName] = res
+			}
+			if err := <-errChan; err != nil {
+				return err
+			}
+		}
+		var results []runResult
+		for _, name := range ruleNames {
+			results = append(results, byName[name])
 		}
-		return ru.Run(ctx, a.AwsConf, true)
+		log.Printf("summary of the rules%s\n%s", dryRunSuffix, formatRunSummary(results))
+		return runResultsError(results)
 	},
 }
+
+// selectRuleNames returns the names of the rules to run. Comma separated names of -rule are
+// returned in the order, and -all returns all rules in the order of the configuration.
+func selectRuleNames(c *Config, rule string, all bool, filter string) ([]string, error) {
+	var candidates []string
+	if rule != "" {
+		for _, name := range strings.Split(rule, ",") {
+			name = strings.TrimSpace(name)
+			if c.GetRuleByName(name) == nil {
+				return nil, fmt.Errorf("no rules found for %s", name)
+			}
+			candidates = append(candidates, name)
+		}
+	} else if all {
+		for _, r := range c.Rules {
+			candidates = append(candidates, r.Name)
+		}
+	}
+	if filter == "" {
+		return candidates, nil
+	}
+	reg, err := regexp.Compile(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -filter: %w", err)
+	}
+	var ruleNames []string
+	for _, name := range candidates {
+		if reg.MatchString(name) {
+			ruleNames = append(ruleNames, name)
+		}
+	}
+	if len(ruleNames) == 0 {
+		return nil, fmt.Errorf("no rules match the filter %q", filter)
+	}
+	return ruleNames, nil
+}
+
+// formatRunSummary renders a table of the started tasks and the failures of each rule
+func formatRunSummary(results []runResult) string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RULE\tSTATUS\tTASKS\tERROR")
+	for _, res := range results {
+		status := "succeeded"
+		switch {
+		case res.skipped:
+			status = "skipped"
+		case res.err != nil:
+			status = "failed"
+		}
+		tasks := "-"
+		if len(res.taskArns) > 0 {
+			tasks = strings.Join(res.taskArns, ",")
+		}
+		errMsg := "-"
+		if res.err != nil {
+			// the first line is enough for the table
+			errMsg, _, _ = strings.Cut(res.err.Error(), "\n")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", res.ruleName, status, tasks, errMsg)
+	}
+	w.Flush()
+	return buf.String()
+}
+
+// runFailedError is returned when some of the rules failed to run.
+// The exit code is the one of the first failed task, if any.
+type runFailedError struct {
+	failed   []string
+	total    int
+	exitCode int
+}
+
+func (e *runFailedError) Error() string {
+	return fmt.Sprintf("%d of %d rule(s) failed: %s", len(e.failed), e.total, strings.Join(e.failed, ", "))
+}
+
+// ExitCode returns the exit code of the first failed task
+func (e *runFailedError) ExitCode() int {
+	return e.exitCode
+}
+
+func runResultsError(results []runResult) error {
+	var rfe *runFailedError
+	for _, res := range results {
+		if res.err == nil {
+			continue
+		}
+		if rfe == nil {
+			rfe = &runFailedError{total: len(results), exitCode: 1}
+			var tfe *taskFailedError
+			if errors.As(res.err, &tfe) {
+				rfe.exitCode = tfe.ExitCode()
+			}
+		}
+		rfe.failed = append(rfe.failed, res.ruleName)
+	}
+	if rfe != nil {
+		return rfe
+	}
+	return nil
+}
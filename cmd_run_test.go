@@ -0,0 +1,73 @@
+package ecschedule
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSelectRuleNames(t *testing.T) {
+	c := &Config{Rules: []*Rule{{Name: "nightly-a"}, {Name: "hourly"}, {Name: "nightly-b"}}}
+	testCases := []struct {
+		name   string
+		rule   string
+		all    bool
+		filter string
+		expect []string
+		err    bool
+	}{
+		{name: "ordered rules", rule: "nightly-b, hourly", expect: []string{"nightly-b", "hourly"}},
+		{name: "all", all: true, expect: []string{"nightly-a", "hourly", "nightly-b"}},
+		{name: "filter", all: true, filter: "^nightly-", expect: []string{"nightly-a", "nightly-b"}},
+		{name: "unknown rule", rule: "hourly,weekly", err: true},
+		{name: "no match", all: true, filter: "weekly", err: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := selectRuleNames(c, tc.rule, tc.all, tc.filter)
+			if tc.err {
+				if err == nil {
+					t.Errorf("error should be returned, but got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.expect) {
+				t.Errorf("got %v, expected %v", got, tc.expect)
+			}
+		})
+	}
+}
+
+func TestRunSummary(t *testing.T) {
+	results := []runResult{
+		{ruleName: "a", taskArns: []string{"arn:aws:ecs:us-east-1:339:task/api/t1"}},
+		{ruleName: "b", taskArns: []string{"arn:aws:ecs:us-east-1:339:task/api/t2"}, err: &taskFailedError{exitCode: 3, msgs: []string{"x"}}},
+		{ruleName: "c", skipped: true},
+	}
+	summary := formatRunSummary(results)
+	for _, e := range []string{
+		"a     succeeded  arn:aws:ecs:us-east-1:339:task/api/t1  -",
+		"b     failed     arn:aws:ecs:us-east-1:339:task/api/t2  task failed:",
+		"c     skipped    -",
+	} {
+		if !strings.Contains(summary, e) {
+			t.Errorf("summary should contain %q, but:\n%s", e, summary)
+		}
+	}
+
+	err := runResultsError(results)
+	var rfe *runFailedError
+	if !errors.As(err, &rfe) {
+		t.Fatalf("runFailedError should be returned, but: %v", err)
+	}
+	if rfe.ExitCode() != 3 || err.Error() != "1 of 3 rule(s) failed: b" {
+		t.Errorf("unexpected error: %s (exit code %d)", err, rfe.ExitCode())
+	}
+	if err := runResultsError(results[:1]); err != nil {
+		t.Errorf("error should be nil, but: %s", err)
+	}
+}
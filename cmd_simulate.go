@@ -0,0 +1,140 @@
+package ecschedule
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+)
+
+var cmdSimulate = &runnerImpl{
+	name:        "simulate",
+	description: "simulate the schedules and report the peaks and the collisions",
+	run: func(ctx context.Context, argv []string, outStream, errStream io.Writer) error {
+		fs := flag.NewFlagSet("ecschedule simulate", flag.ContinueOnError)
+		fs.SetOutput(errStream)
+		var (
+			conf     = fs.String("conf", "", "configuration")
+			rule     = fs.String("rule", "", "rules to simulate (comma separated, default: all rules)")
+			filter   = fs.String("filter", "", "regexp to filter the rules to simulate by name")
+			from     = fs.String("from", "", "start of the simulation in RFC3339 (default: now)")
+			to       = fs.String("to", "", "end of the simulation in RFC3339 (default: 24 hours after -from)")
+			format   = fs.String("format", "text", "output format: text or json")
+			top      = fs.Int("top", 10, "number of the collisions to show in text (0: all)")
+			timeline = fs.Bool("timeline", false, "show the timeline of the executions in text")
+		)
+		if err := fs.Parse(argv); err != nil {
+			return err
+		}
+		if *format != "text" && *format != "json" {
+			return fmt.Errorf("unknown format %q: text or json expected", *format)
+		}
+		start := time.Now()
+		if *from != "" {
+			t, err := time.Parse(time.RFC3339, *from)
+			if err != nil {
+				return fmt.Errorf("invalid -from: %w", err)
+			}
+			start = t
+		}
+		end := start.Add(24 * time.Hour)
+		if *to != "" {
+			t, err := time.Parse(time.RFC3339, *to)
+			if err != nil {
+				return fmt.Errorf("invalid -to: %w", err)
+			}
+			end = t
+		}
+		if !start.Before(end) {
+			return errors.New("-to must be after -from")
+		}
+		a := getApp(ctx)
+		c := a.Config
+		if *conf != "" {
+			f, err := os.Open(*conf)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			c, err = LoadConfig(ctx, f, a.AccountID, *conf, a.loadConfigOptions()...)
+			if err != nil {
+				return err
+			}
+		}
+		if c == nil {
+			return errors.New("-conf option required")
+		}
+		ruleNames, err := selectRuleNames(c, *rule, *rule == "", *filter)
+		if err != nil {
+			return err
+		}
+
+		ts := newTaskSizer(func(region string) ecsClient {
+			return ecs.NewFromConfig(a.AwsConf, func(o *ecs.Options) {
+				o.Region = region
+			})
+		})
+		var rules []*simulatedRule
+		for _, name := range ruleNames {
+			sr, err := newSimulatedRule(ctx, c.GetRuleByName(name), ts)
+			if err != nil {
+				return err
+			}
+			if sr != nil {
+				rules = append(rules, sr)
+			}
+		}
+		sim := simulate(rules, start, end)
+		if *format == "json" {
+			enc := json.NewEncoder(outStream)
+			enc.SetIndent("", "  ")
+			return enc.Encode(sim)
+		}
+		fmt.Fprint(outStream, formatSimulation(sim, len(rules), *top, *timeline))
+		return nil
+	},
+}
+
+func formatSimulation(sim *simulation, ruleCount, top int, timeline bool) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "simulated %s - %s: %d execution(s) of %d rule(s)\n",
+		sim.From.Format(time.RFC3339), sim.To.Format(time.RFC3339), len(sim.Executions), ruleCount)
+	if len(sim.Executions) == 0 {
+		return buf.String()
+	}
+	fmt.Fprintf(&buf, "peak tasks:  %d at %s\n", sim.PeakTasks.Value, sim.PeakTasks.At.Format(time.RFC3339))
+	fmt.Fprintf(&buf, "peak vCPU:   %g at %s\n", float64(sim.PeakCPU.Value)/1024, sim.PeakCPU.At.Format(time.RFC3339))
+	fmt.Fprintf(&buf, "peak memory: %d MiB at %s\n", sim.PeakMemory.Value, sim.PeakMemory.At.Format(time.RFC3339))
+
+	if len(sim.Collisions) > 0 {
+		collisions := sim.Collisions
+		if top > 0 && len(collisions) > top {
+			collisions = collisions[:top]
+		}
+		fmt.Fprintf(&buf, "\nrules firing in the same minute (%d of %d):\n", len(collisions), len(sim.Collisions))
+		for _, co := range collisions {
+			fmt.Fprintf(&buf, "%s  %d rules: %s\n", co.Time.Format(time.RFC3339), len(co.Rules), strings.Join(co.Rules, ", "))
+		}
+	}
+
+	if timeline {
+		fmt.Fprintln(&buf, "\ntimeline:")
+		w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "START\tEND\tRULE\tTASKS\tVCPU\tMEMORY")
+		for _, e := range sim.Executions {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%g\t%d MiB\n", e.Start.Format(time.RFC3339), e.End.Format(time.RFC3339),
+				e.Rule, e.Tasks, float64(e.CPU)/1024, e.Memory)
+		}
+		w.Flush()
+	}
+	return buf.String()
+}
@@ -0,0 +1,206 @@
+package ecschedule
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+var (
+	cmdEnable  = newStateCommand(true)
+	cmdDisable = newStateCommand(false)
+)
+
+// loadConfigForState loads the configuration for the subcommands changing the state of the rules
+func loadConfigForState(ctx context.Context, conf string) (*Config, error) {
+	a := getApp(ctx)
+	c := a.Config
+	if conf != "" {
+		f, err := os.Open(conf)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		c, err = LoadConfig(ctx, f, a.AccountID, conf, a.loadConfigOptions()...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if c == nil {
+		return nil, errors.New("-conf option required")
+	}
+	return c, nil
+}
+
+// newStateCommand returns the enable or disable subcommand, which flips only the state of the rules
+func newStateCommand(enabled bool) *runnerImpl {
+	name, verb := "disable", "disabled"
+	if enabled {
+		name, verb = "enable", "enabled"
+	}
+	return &runnerImpl{
+		name:        name,
+		description: name + " the rule without changing the configuration",
+		run: func(ctx context.Context, argv []string, outStream, errStream io.Writer) error {
+			fs := flag.NewFlagSet("ecschedule "+name, flag.ContinueOnError)
+			fs.SetOutput(errStream)
+			var (
+				conf   = fs.String("conf", "", "configuration")
+				rule   = fs.String("rule", "", "rule (comma separated)")
+				all    = fs.Bool("all", false, name+" all rules")
+				filter = fs.String("filter", "", "regexp to filter the rules by name")
+				dryRun = fs.Bool("dry-run", false, "dry run")
+				reason *string
+			)
+			if !enabled {
+				reason = fs.String("reason", "", "reason to disable the rule, recorded in the tag of the rule")
+			}
+			if err := fs.Parse(argv); err != nil {
+				return err
+			}
+			if !*all && *rule == "" {
+				return errors.New("-rule or -all option required")
+			}
+			a := getApp(ctx)
+			c, err := loadConfigForState(ctx, *conf)
+			if err != nil {
+				return err
+			}
+			ruleNames, err := selectRuleNames(c, *rule, *all, *filter)
+			if err != nil {
+				return err
+			}
+
+			var by, why string
+			if !enabled {
+				by, err = getCallerARN(ctx, a.AwsConf)
+				if err != nil {
+					return fmt.Errorf("failed to get the caller identity: %w", err)
+				}
+				why = *reason
+			}
+			var dryRunSuffix string
+			if *dryRun {
+				dryRunSuffix = " (dry-run)"
+			}
+			for _, ruleName := range ruleNames {
+				ru := c.GetRuleByName(ruleName)
+				if err := ru.SetState(ctx, a.AwsConf, enabled, by, why, *dryRun); err != nil {
+					return err
+				}
+				log.Printf("✅ %s the rule %q%s", verb, ruleName, dryRunSuffix)
+			}
+			return nil
+		},
+	}
+}
+
+var cmdPause = &runnerImpl{
+	name:        "pause",
+	description: "disable the rule until the time",
+	run: func(ctx context.Context, argv []string, outStream, errStream io.Writer) error {
+		fs := flag.NewFlagSet("ecschedule pause", flag.ContinueOnError)
+		fs.SetOutput(errStream)
+		var (
+			conf   = fs.String("conf", "", "configuration")
+			rule   = fs.String("rule", "", "rule (comma separated)")
+			all    = fs.Bool("all", false, "pause all rules")
+			filter = fs.String("filter", "", "regexp to filter the rules by name")
+			until  = fs.String("until", "", "time to resume the rule in RFC 3339 (e.g. 2026-12-27T00:00:00Z)")
+			reason = fs.String("reason", "", "reason to pause the rule, recorded in the tag of the rule")
+			dryRun = fs.Bool("dry-run", false, "dry run")
+		)
+		if err := fs.Parse(argv); err != nil {
+			return err
+		}
+		if !*all && *rule == "" {
+			return errors.New("-rule or -all option required")
+		}
+		if *until == "" {
+			return errors.New("-until option required")
+		}
+		untilTime, err := time.Parse(time.RFC3339, *until)
+		if err != nil {
+			return fmt.Errorf("invalid -until: %w", err)
+		}
+		if !untilTime.After(time.Now()) {
+			return fmt.Errorf("-until %s is in the past", *until)
+		}
+		a := getApp(ctx)
+		c, err := loadConfigForState(ctx, *conf)
+		if err != nil {
+			return err
+		}
+		ruleNames, err := selectRuleNames(c, *rule, *all, *filter)
+		if err != nil {
+			return err
+		}
+		by, err := getCallerARN(ctx, a.AwsConf)
+		if err != nil {
+			return fmt.Errorf("failed to get the caller identity: %w", err)
+		}
+		var dryRunSuffix string
+		if *dryRun {
+			dryRunSuffix = " (dry-run)"
+		}
+		for _, ruleName := range ruleNames {
+			ru := c.GetRuleByName(ruleName)
+			if err := ru.Pause(ctx, a.AwsConf, untilTime, by, *reason, *dryRun); err != nil {
+				return err
+			}
+			log.Printf("✅ paused the rule %q until %s%s", ruleName, untilTime.UTC().Format(time.RFC3339), dryRunSuffix)
+		}
+		return nil
+	},
+}
+
+var cmdResumeDue = &runnerImpl{
+	name:        "resume-due",
+	description: "enable the paused rules whose pause has expired",
+	run: func(ctx context.Context, argv []string, outStream, errStream io.Writer) error {
+		fs := flag.NewFlagSet("ecschedule resume-due", flag.ContinueOnError)
+		fs.SetOutput(errStream)
+		var (
+			conf   = fs.String("conf", "", "configuration")
+			rule   = fs.String("rule", "", "rule (comma separated, default: all rules)")
+			filter = fs.String("filter", "", "regexp to filter the rules by name")
+			dryRun = fs.Bool("dry-run", false, "dry run")
+		)
+		if err := fs.Parse(argv); err != nil {
+			return err
+		}
+		a := getApp(ctx)
+		c, err := loadConfigForState(ctx, *conf)
+		if err != nil {
+			return err
+		}
+		ruleNames, err := selectRuleNames(c, *rule, true, *filter)
+		if err != nil {
+			return err
+		}
+		var dryRunSuffix string
+		if *dryRun {
+			dryRunSuffix = " (dry-run)"
+		}
+		now := time.Now()
+		var resumed int
+		for _, ruleName := range ruleNames {
+			ru := c.GetRuleByName(ruleName)
+			ok, err := ru.ResumeIfDue(ctx, a.AwsConf, now, *dryRun)
+			if err != nil {
+				return err
+			}
+			if ok {
+				resumed++
+				log.Printf("✅ resumed the rule %q%s", ruleName, dryRunSuffix)
+			}
+		}
+		log.Printf("%d rule(s) resumed%s", resumed, dryRunSuffix)
+		return nil
+	},
+}
@@ -38,6 +38,18 @@ func init() {
 		cmdDump,
 		cmdRun,
 		cmdDiff,
+		cmdList,
+		cmdStatus,
+		cmdSimulate,
+		cmdExport,
+		cmdGraph,
+		cmdExplain,
+		cmdEnable,
+		cmdDisable,
+		cmdPause,
+		cmdResumeDue,
+		cmdDelete,
+		cmdMigrateToScheduler,
 	)
 }
 
@@ -53,10 +65,13 @@ type runner interface {
 	Name() string
 	Description() string
 	Run(context.Context, []string, io.Writer, io.Writer) error
+	// Offline reports whether the subcommand works without AWS access
+	Offline() bool
 }
 
 type runnerImpl struct {
 	name, description string
+	offline           bool
 	run               func(context.Context, []string, io.Writer, io.Writer) error
 }
 
@@ -68,6 +83,10 @@ func (ri *runnerImpl) Description() string {
 	return ri.description
 }
 
+func (ri *runnerImpl) Offline() bool {
+	return ri.offline
+}
+
 func (ri *runnerImpl) Run(ctx context.Context, argv []string, outStream io.Writer, errStream io.Writer) error {
 	return ri.run(ctx, argv, outStream, errStream)
 }
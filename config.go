@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/goccy/go-yaml"
 	gc "github.com/kayac/go-config"
@@ -28,6 +29,14 @@ type BaseConfig struct {
 	Cluster    string `yaml:"cluster" json:"cluster"`
 	AccountID  string `yaml:"-" json:"-"`
 	TrackingID string `yaml:"trackingId,omitempty" json:"trackingId,omitempty"`
+	// Backend is the API which manages schedules: "events" (CloudWatch Events, default) or "scheduler" (EventBridge Scheduler)
+	Backend       string `yaml:"backend,omitempty" json:"backend,omitempty"`
+	ScheduleGroup string `yaml:"scheduleGroup,omitempty" json:"scheduleGroup,omitempty"`
+	// EventBusName is the event bus of the rules. The default event bus is used when omitted.
+	EventBusName string `yaml:"eventBusName,omitempty" json:"eventBusName,omitempty"`
+	// ScheduleTimezone is the IANA timezone which the cron expressions are written in. They are
+	// translated into UTC for the events backend.
+	ScheduleTimezone string `yaml:"scheduleTimezone,omitempty" json:"scheduleTimezone,omitempty"`
 }
 
 // Config config
@@ -41,6 +50,30 @@ type Config struct {
 	dir           string
 }
 
+// ruleARNs returns ARNs of the CloudWatch Events rules in ruleNames
+func (c *Config) ruleARNs(ruleNames []string) []string {
+	var arns []string
+	for _, name := range ruleNames {
+		if ru := c.GetRuleByName(name); ru != nil && !ru.useScheduler() {
+			arns = append(arns, ru.ruleARN())
+		}
+	}
+	return arns
+}
+
+// scheduleNames returns the schedule groups and the names of the rules in ruleNames joined with
+// "/", so that schedules are told from the ones of the same name in other groups. The rules of the
+// events backend are included not to prune the schedules created by migrate-to-scheduler for them.
+func (c *Config) scheduleNames(ruleNames []string) []string {
+	var names []string
+	for _, name := range ruleNames {
+		if ru := c.GetRuleByName(name); ru != nil {
+			names = append(names, ru.scheduleGroup()+"/"+ru.Name)
+		}
+	}
+	return names
+}
+
 // GetRuleByName gets rule by name
 func (c *Config) GetRuleByName(name string) *Rule {
 	for _, r := range c.Rules {
@@ -64,6 +97,10 @@ func (c *Config) cronValidate() error {
 	// XXX: I'd like to use multiple errors here and format the error messages at the very end.
 	var errMsgs []string
 	for _, r := range c.Rules {
+		if r.ScheduleExpression == "" && r.EventPattern != nil {
+			// validated in eventPatternValidate
+			continue
+		}
 		err := validateCronExpression(r.ScheduleExpression)
 		if err != nil {
 			errMsgs = append(errMsgs, fmt.Sprintf("\trule %q: %s", r.Name, err))
@@ -75,6 +112,92 @@ func (c *Config) cronValidate() error {
 	return nil
 }
 
+func (c *Config) expectedDurationValidate() error {
+	var errMsgs []string
+	for _, r := range c.Rules {
+		if _, err := r.expectedDuration(); err != nil {
+			errMsgs = append(errMsgs, fmt.Sprintf("\trule %q: %s", r.Name, err))
+		}
+	}
+	if len(errMsgs) > 0 {
+		return fmt.Errorf("expected duration validation errors:\n%s", strings.Join(errMsgs, "\n"))
+	}
+	return nil
+}
+
+func (c *Config) eventPatternValidate() error {
+	var errMsgs []string
+	for _, r := range c.Rules {
+		if r.EventPattern == nil {
+			continue
+		}
+		if r.ScheduleExpression != "" {
+			errMsgs = append(errMsgs, fmt.Sprintf("\trule %q: scheduleExpression and eventPattern are mutually exclusive", r.Name))
+			continue
+		}
+		if err := validateEventPattern(r.EventPattern); err != nil {
+			errMsgs = append(errMsgs, fmt.Sprintf("\trule %q: %s", r.Name, err))
+		}
+	}
+	if len(errMsgs) > 0 {
+		return fmt.Errorf("event pattern validation errors:\n%s", strings.Join(errMsgs, "\n"))
+	}
+	return nil
+}
+
+func (c *Config) targetValidate() error {
+	var errMsgs []string
+	for _, r := range c.Rules {
+		targets := r.targets()
+		if len(targets) == 0 {
+			errMsgs = append(errMsgs, fmt.Sprintf("\trule %q: no targets specified", r.Name))
+			continue
+		}
+		ids := map[string]bool{}
+		for _, ta := range targets {
+			id := ta.targetID(r)
+			if ids[id] {
+				errMsgs = append(errMsgs, fmt.Sprintf("\trule %q: duplicate target id %q", r.Name, id))
+			}
+			ids[id] = true
+			if err := ta.validatePlacement(); err != nil {
+				errMsgs = append(errMsgs, fmt.Sprintf("\trule %q: target %q: %s", r.Name, id, err))
+			}
+			if rp := ta.RetryPolicy; rp != nil {
+				if err := rp.validate(); err != nil {
+					errMsgs = append(errMsgs, fmt.Sprintf("\trule %q: target %q: %s", r.Name, id, err))
+				}
+			}
+		}
+	}
+	if len(errMsgs) > 0 {
+		return fmt.Errorf("target validation errors:\n%s", strings.Join(errMsgs, "\n"))
+	}
+	return nil
+}
+
+func (c *Config) backendValidate() error {
+	var errMsgs []string
+	for _, r := range c.Rules {
+		if err := r.validateBackend(); err != nil {
+			errMsgs = append(errMsgs, fmt.Sprintf("\trule %q: %s", r.Name, err))
+		}
+	}
+	if len(errMsgs) > 0 {
+		return fmt.Errorf("backend validation errors:\n%s", strings.Join(errMsgs, "\n"))
+	}
+	return nil
+}
+
+func (c *Config) usesScheduler() bool {
+	for _, r := range c.Rules {
+		if r.useScheduler() {
+			return true
+		}
+	}
+	return false
+}
+
 func validateCronExpression(exp string) error {
 	if strings.HasPrefix(exp, "rate(") && strings.HasSuffix(exp, ")") {
 		return nil
@@ -98,6 +221,7 @@ func validateCronExpression(exp string) error {
 type loadConfigOptions struct {
 	extStr  map[string]string
 	extCode map[string]string
+	now     time.Time
 }
 
 // LoadConfigOption configures LoadConfig
@@ -127,6 +251,14 @@ func WithExtCode(vars map[string]string) LoadConfigOption {
 	}
 }
 
+// WithNow sets the time from which the daylight saving time transitions of scheduleTimezone are
+// checked. The current time is used when omitted.
+func WithNow(now time.Time) LoadConfigOption {
+	return func(o *loadConfigOptions) {
+		o.now = now
+	}
+}
+
 // LoadConfig loads config
 func LoadConfig(ctx context.Context, r io.Reader, accountID string, confPath string, opts ...LoadConfigOption) (*Config, error) {
 	var o loadConfigOptions
@@ -149,6 +281,12 @@ func LoadConfig(ctx context.Context, r io.Reader, accountID string, confPath str
 	if err := c.cronValidate(); err != nil {
 		return nil, err
 	}
+	if err := c.expectedDurationValidate(); err != nil {
+		return nil, err
+	}
+	if err := c.eventPatternValidate(); err != nil {
+		return nil, err
+	}
 	c.AccountID = accountID
 	if c.TrackingID == "" {
 		c.TrackingID = c.Cluster
@@ -175,6 +313,19 @@ func LoadConfig(ctx context.Context, r io.Reader, accountID string, confPath str
 	for _, r := range c.Rules {
 		r.mergeBaseConfig(c.BaseConfig, c.Role)
 	}
+	if err := c.targetValidate(); err != nil {
+		return nil, err
+	}
+	if err := c.backendValidate(); err != nil {
+		return nil, err
+	}
+	now := o.now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if err := c.convertScheduleTimezones(now); err != nil {
+		return nil, err
+	}
 	return &c, nil
 }
 
@@ -279,3 +279,82 @@ func TestCronValidate(t *testing.T) {
 		t.Errorf("unexpected error message\nwant:\n%s\n\ngot:\n%s", e, g)
 	}
 }
+
+func TestExpectedDurationValidate(t *testing.T) {
+	c := &Config{
+		Rules: []*Rule{
+			{Name: "rule-1", ScheduleExpression: "cron(0 0 * * ? *)", ExpectedDuration: "15m"},
+			{Name: "rule-2", ScheduleExpression: "cron(0 0 * * ? *)"},
+			{Name: "rule-3", ScheduleExpression: "cron(0 0 * * ? *)", ExpectedDuration: "15 minutes"},
+			{Name: "rule-4", ScheduleExpression: "cron(0 0 * * ? *)", ExpectedDuration: "-1h"},
+		},
+	}
+	if err := c.cronValidate(); err != nil {
+		t.Errorf("expectedDuration should not be validated with the schedule expressions, but: %s", err)
+	}
+	err := c.expectedDurationValidate()
+	if err == nil {
+		t.Fatalf("error should be occurred, but nil")
+	}
+	e := "expected duration validation errors:\n" +
+		"\trule \"rule-3\": invalid expectedDuration: time: unknown unit \" minutes\" in duration \"15 minutes\"\n" +
+		"\trule \"rule-4\": invalid expectedDuration: \"-1h\" is negative"
+	if g := err.Error(); g != e {
+		t.Errorf("unexpected error message\nwant:\n%s\n\ngot:\n%s", e, g)
+	}
+}
+
+func TestLoadConfig_targets(t *testing.T) {
+	path := "testdata/sample6.yaml"
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	c, err := LoadConfig(context.Background(), f, "339", path)
+	if err != nil {
+		t.Fatalf("error should be nil, but: %s", err)
+	}
+
+	ru := c.GetRuleByName("fanout-task-name")
+	targets := ru.targets()
+	if len(targets) != 2 {
+		t.Fatalf("expected 2 targets, but: %d", len(targets))
+	}
+	if g, e := targets[0].Role, "ecsEventsRole"; g != e {
+		t.Errorf("role of the first target should be %q, but: %q", e, g)
+	}
+	if g, e := targets[1].Role, "ecsEventsRole2"; g != e {
+		t.Errorf("role of the second target should be %q, but: %q", e, g)
+	}
+	if g, e := targets[1].targetARN(ru), "arn:aws:ecs:us-east-1:339:cluster/batch"; g != e {
+		t.Errorf("cluster of the second target should be %q, but: %q", e, g)
+	}
+}
+
+func TestTargetValidate(t *testing.T) {
+	c := &Config{
+		Rules: []*Rule{
+			{Name: "rule-1", Target: &Target{TaskDefinition: "task1"}}, // valid
+			{Name: "rule-2", Targets: []*Target{ // valid
+				{TaskDefinition: "task1"},
+				{TargetID: "rule-2-second", TaskDefinition: "task2"},
+			}},
+			{Name: "rule-3"}, // no targets
+			{Name: "rule-4", Target: &Target{TaskDefinition: "task1"}, Targets: []*Target{ // both default to the rule name
+				{TaskDefinition: "task2"},
+			}},
+		},
+	}
+	err := c.targetValidate()
+	if err == nil {
+		t.Fatalf("error should be occurred, but nil")
+	}
+	e := "target validation errors:\n" +
+		"\trule \"rule-3\": no targets specified\n" +
+		"\trule \"rule-4\": duplicate target id \"rule-4\""
+	if g := err.Error(); g != e {
+		t.Errorf("unexpected error message\nwant:\n%s\n\ngot:\n%s", e, g)
+	}
+}
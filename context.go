@@ -2,6 +2,7 @@ package ecschedule
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 )
@@ -16,6 +17,8 @@ type app struct {
 	AwsConf   aws.Config
 	ExtStr    map[string]string
 	ExtCode   map[string]string
+	// Now is the time when the command started
+	Now time.Time
 }
 
 func (a *app) loadConfigOptions() []LoadConfigOption {
@@ -26,6 +29,9 @@ func (a *app) loadConfigOptions() []LoadConfigOption {
 	if len(a.ExtCode) > 0 {
 		opts = append(opts, WithExtCode(a.ExtCode))
 	}
+	if !a.Now.IsZero() {
+		opts = append(opts, WithNow(a.Now))
+	}
 	return opts
 }
 
@@ -69,7 +69,7 @@ func TestFormatDiff(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := formatDiff(tt.ruleName, tt.from, tt.to, tt.format)
+			got := formatDiff(tt.ruleName, tt.from, tt.to, tt.format, explainOptions{})
 			if tt.want == "" {
 				if got != "" {
 					t.Errorf("formatDiff() = %q, want empty string", got)
@@ -88,21 +88,21 @@ func TestColorControl(t *testing.T) {
 	to := "name: test\nvalue: 2\n"
 
 	// Pretty format is always colored
-	prettyOutput := formatDiff("test", from, to, diffFormatPrettyColored)
+	prettyOutput := formatDiff("test", from, to, diffFormatPrettyColored, explainOptions{})
 	if !strings.Contains(prettyOutput, "\x1b[") {
 		t.Error("Pretty format should always contain ANSI escape codes")
 	}
 
 	// Unified format with color.NoColor=false is colored
 	color.NoColor = false
-	unifiedColored := formatDiff("test", from, to, diffFormatUnified)
+	unifiedColored := formatDiff("test", from, to, diffFormatUnified, explainOptions{})
 	if !strings.Contains(unifiedColored, "\x1b[") {
 		t.Error("Unified format should contain ANSI escape codes when color.NoColor=false")
 	}
 
 	// Unified format with color.NoColor=true is plain
 	color.NoColor = true
-	unifiedPlain := formatDiff("test", from, to, diffFormatUnified)
+	unifiedPlain := formatDiff("test", from, to, diffFormatUnified, explainOptions{})
 	if strings.Contains(unifiedPlain, "\x1b[") {
 		t.Error("Unified format should not contain ANSI escape codes when color.NoColor=true")
 	}
@@ -117,7 +117,7 @@ func TestFormatDiffMultiByte(t *testing.T) {
 
 	// Test multi-byte character handling in Unified format
 	color.NoColor = true
-	output := formatDiff("test", from, to, diffFormatUnified)
+	output := formatDiff("test", from, to, diffFormatUnified, explainOptions{})
 	if !strings.Contains(output, "日本語") {
 		t.Error("Unified format should handle multi-byte characters")
 	}
@@ -148,6 +148,6 @@ func BenchmarkFormatDiff(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		formatDiff("benchmark-rule", from, to, diffFormatUnified)
+		formatDiff("benchmark-rule", from, to, diffFormatUnified, explainOptions{})
 	}
 }
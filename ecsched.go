@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 )
@@ -79,19 +80,31 @@ func Run(ctx context.Context, argv []string, outStream, errStream io.Writer) err
 	if *ver {
 		return printVersion(outStream)
 	}
+	argv = fs.Args()
+	if len(argv) < 1 {
+		return fmt.Errorf("no subcommand specified")
+	}
+	rnr, ok := cmder.dispatch[argv[0]]
+	if !ok {
+		return fmt.Errorf("unknown subcommand: %s", argv[0])
+	}
 	awsConf, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
 		return err
 	}
-	accountID, err := GetAWSAccountID(awsConf)
-	if err != nil {
-		return err
+	var accountID string
+	if !rnr.Offline() {
+		accountID, err = GetAWSAccountID(awsConf)
+		if err != nil {
+			return err
+		}
 	}
 	a := &app{
 		AccountID: accountID,
 		AwsConf:   awsConf,
 		ExtStr:    extStr.pairs,
 		ExtCode:   extCode.pairs,
+		Now:       time.Now(),
 	}
 	ctx = setApp(ctx, a)
 	if *conf != "" {
@@ -107,14 +120,6 @@ func Run(ctx context.Context, argv []string, outStream, errStream io.Writer) err
 		a.Config = c
 	}
 	ctx = setApp(ctx, a)
-	argv = fs.Args()
-	if len(argv) < 1 {
-		return fmt.Errorf("no subcommand specified")
-	}
-	rnr, ok := cmder.dispatch[argv[0]]
-	if !ok {
-		return fmt.Errorf("unknown subcommand: %s", argv[0])
-	}
 	return rnr.Run(ctx, argv[1:], outStream, errStream)
 }
 
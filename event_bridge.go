@@ -11,6 +11,11 @@ import (
 	"golang.org/x/exp/slices"
 )
 
+const (
+	trackingIDTagKey    = "ecschedule:tracking-id"
+	defaultEventBusName = "default"
+)
+
 type Query struct {
 	ResourceTypeFIlters []string    `json:"ResourceTypeFilters"`
 	TagFilters          []TagFilter `json:"TagFilters"`
@@ -21,36 +26,54 @@ type TagFilter struct {
 	Values []string `json:"Values"`
 }
 
-// Extract the Rule associated with trackingId and extract those that are not included in ruleNames.
-func extractOrphanedRules(ctx context.Context, awsConf aws.Config, base *BaseConfig, ruleNames []string) ([]*Rule, error) {
-	trackedRuleNames, err := listTrackedRules(ctx, awsConf, base.TrackingID)
+// Extract the Rule associated with trackingId and extract those that are not included in ruleARNs.
+// Rules on all event buses are extracted.
+func extractOrphanedRules(ctx context.Context, awsConf aws.Config, base *BaseConfig, ruleARNs []string) ([]*Rule, error) {
+	trackedRuleARNs, err := listTrackedRules(ctx, awsConf, base.TrackingID)
 	if err != nil {
 		return nil, err
 	}
 
-	var orphanedRuleNames []string
-	for _, trackedRuleName := range trackedRuleNames {
-		if !slices.Contains(ruleNames, trackedRuleName) {
-			orphanedRuleNames = append(orphanedRuleNames, trackedRuleName)
+	var orphanedRuleARNs []string
+	for _, trackedRuleARN := range trackedRuleARNs {
+		if !slices.Contains(ruleARNs, trackedRuleARN) {
+			orphanedRuleARNs = append(orphanedRuleARNs, trackedRuleARN)
 		}
 	}
 
 	var orphanedRules []*Rule
-	for _, orphanedRuleName := range orphanedRuleNames {
-		orphanedRule, err := NewRuleFromRemote(ctx, awsConf, base, orphanedRuleName)
+	for _, orphanedRuleARN := range orphanedRuleARNs {
+		eventBusName, ruleName := parseRuleARN(orphanedRuleARN)
+		bc := *base
+		bc.Backend = backendEvents
+		bc.EventBusName = eventBusName
+		orphanedRule, err := NewRuleFromRemote(ctx, awsConf, &bc, ruleName)
 		if err != nil {
 			return nil, err
 		}
-		orphanedRules = append(orphanedRules, orphanedRule)
+		if orphanedRule != nil {
+			orphanedRules = append(orphanedRules, orphanedRule)
+		}
 	}
 
 	return orphanedRules, nil
 }
 
+// parseRuleARN returns the event bus name and the rule name in the ARN of the rule.
+// The event bus name is empty for the default event bus.
+func parseRuleARN(arn string) (eventBusName, ruleName string) {
+	resource := arn[strings.Index(arn, ":rule/")+len(":rule/"):]
+	if i := strings.Index(resource, "/"); i >= 0 {
+		return resource[:i], resource[i+1:]
+	}
+	return "", resource
+}
+
 // Using the SearchResources API of the AWS Resource Groups service, extract the Rule with
 // the following tags from `AWS::Events::Rule`.
 // - Key: 'ecschedule:tracking-id'
 // - Value: base.TrackingId
+// It returns ARNs of the rules.
 func listTrackedRules(ctx context.Context, awsConf aws.Config, trackingId string) ([]string, error) {
 	svc := resourcegroups.NewFromConfig(awsConf, func(o *resourcegroups.Options) {
 		o.Region = awsConf.Region
@@ -59,7 +82,7 @@ func listTrackedRules(ctx context.Context, awsConf aws.Config, trackingId string
 		ResourceTypeFIlters: []string{"AWS::Events::Rule"},
 		TagFilters: []TagFilter{
 			{
-				Key:    "ecschedule:tracking-id",
+				Key:    trackingIDTagKey,
 				Values: []string{trackingId},
 			},
 		},
@@ -76,7 +99,7 @@ func listTrackedRules(ctx context.Context, awsConf aws.Config, trackingId string
 		},
 	}
 
-	var ruleNames []string
+	var ruleARNs []string
 	for {
 		result, err := svc.SearchResources(ctx, input)
 		if err != nil {
@@ -85,10 +108,7 @@ func listTrackedRules(ctx context.Context, awsConf aws.Config, trackingId string
 
 		if result.ResourceIdentifiers != nil {
 			for _, identifier := range result.ResourceIdentifiers {
-				arn := *identifier.ResourceArn
-				arnElements := strings.Split(arn, "/")
-				ruleName := arnElements[len(arnElements)-1]
-				ruleNames = append(ruleNames, ruleName)
+				ruleARNs = append(ruleARNs, *identifier.ResourceArn)
 			}
 		}
 
@@ -99,5 +119,5 @@ func listTrackedRules(ctx context.Context, awsConf aws.Config, trackingId string
 		input.NextToken = result.NextToken
 	}
 
-	return ruleNames, nil
+	return ruleARNs, nil
 }
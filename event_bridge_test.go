@@ -0,0 +1,61 @@
+package ecschedule
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestRule_ruleARN_eventBus(t *testing.T) {
+	testCases := []struct {
+		eventBusName string
+		expect       string
+	}{
+		{"", "arn:aws:events:us-east-1:339:rule/hoge"},
+		{"default", "arn:aws:events:us-east-1:339:rule/hoge"},
+		{"batch", "arn:aws:events:us-east-1:339:rule/batch/hoge"},
+	}
+	for _, tc := range testCases {
+		ru := &Rule{
+			Name:       "hoge",
+			Target:     &Target{TaskDefinition: "task1"},
+			BaseConfig: &BaseConfig{Region: "us-east-1", AccountID: "339", EventBusName: tc.eventBusName},
+		}
+		if g := ru.ruleARN(); g != tc.expect {
+			t.Errorf("ruleARN should be %q, but: %q", tc.expect, g)
+		}
+		eventBusName, ruleName := parseRuleARN(tc.expect)
+		if ruleName != "hoge" {
+			t.Errorf("rule name should be %q, but: %q", "hoge", ruleName)
+		}
+		if e := tc.eventBusName; e != "default" && eventBusName != e {
+			t.Errorf("event bus name should be %q, but: %q", e, eventBusName)
+		}
+		if g, e := aws.ToString(ru.PutRuleInput().EventBusName), tc.eventBusName; g != e {
+			t.Errorf("event bus name of PutRuleInput should be %q, but: %q", e, g)
+		}
+		if g, e := aws.ToString(ru.PutTargetsInput().EventBusName), tc.eventBusName; g != e {
+			t.Errorf("event bus name of PutTargetsInput should be %q, but: %q", e, g)
+		}
+	}
+}
+
+func TestConfig_ruleARNs(t *testing.T) {
+	base := &BaseConfig{Region: "us-east-1", AccountID: "339", EventBusName: "batch"}
+	c := &Config{
+		BaseConfig: base,
+		Rules: []*Rule{
+			{Name: "rule-1", BaseConfig: base},
+			{Name: "rule-2", BaseConfig: &BaseConfig{Region: "us-east-1", AccountID: "339"}},
+			{Name: "rule-3", BaseConfig: &BaseConfig{Backend: backendScheduler}},
+		},
+	}
+	g := c.ruleARNs([]string{"rule-1", "rule-2", "rule-3"})
+	e := []string{
+		"arn:aws:events:us-east-1:339:rule/batch/rule-1",
+		"arn:aws:events:us-east-1:339:rule/rule-2",
+	}
+	if len(g) != len(e) || g[0] != e[0] || g[1] != e[1] {
+		t.Errorf("ruleARNs should be %v, but: %v", e, g)
+	}
+}
@@ -0,0 +1,79 @@
+package ecschedule
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// eventPatternJSON returns the event pattern of the rule in JSON. It returns nil when the rule
+// has no event pattern.
+func (r *Rule) eventPatternJSON() (*string, error) {
+	if r.EventPattern == nil {
+		return nil, nil
+	}
+	// keep operators such as ">" as they are instead of escaping them for HTML
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(r.EventPattern); err != nil {
+		return nil, err
+	}
+	s := strings.TrimSuffix(buf.String(), "\n")
+	return &s, nil
+}
+
+// parseEventPattern parses the event pattern in JSON into the canonical form, which holds
+// all numbers as float64 regardless of whether it comes from YAML or JSON.
+func parseEventPattern(s string) (map[string]interface{}, error) {
+	var p map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &p); err != nil {
+		return nil, fmt.Errorf("invalid eventPattern: %w", err)
+	}
+	return p, nil
+}
+
+// canonicalEventPattern returns the event pattern of the rule in the same form as the one
+// reconstructed from the remote rule.
+func (r *Rule) canonicalEventPattern() (map[string]interface{}, error) {
+	s, err := r.eventPatternJSON()
+	if err != nil || s == nil {
+		return nil, err
+	}
+	return parseEventPattern(*s)
+}
+
+// validateEventPattern validates the event pattern locally. EventBridge requires every
+// field in the pattern to be either an array of the values to match or a nested object.
+func validateEventPattern(p map[string]interface{}) error {
+	if len(p) == 0 {
+		return errors.New("eventPattern must not be empty")
+	}
+	if _, err := json.Marshal(p); err != nil {
+		return fmt.Errorf("invalid eventPattern: %w", err)
+	}
+	return validateEventPatternFields("", p)
+}
+
+func validateEventPatternFields(prefix string, p map[string]interface{}) error {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		switch v := p[k].(type) {
+		case map[string]interface{}:
+			if err := validateEventPatternFields(prefix+k+".", v); err != nil {
+				return err
+			}
+		case []interface{}:
+		default:
+			return fmt.Errorf("invalid eventPattern: %q must be an array or an object", prefix+k)
+		}
+	}
+	return nil
+}
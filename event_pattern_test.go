@@ -0,0 +1,85 @@
+package ecschedule
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/goccy/go-yaml"
+)
+
+func TestLoadConfig_eventPattern(t *testing.T) {
+	path := "testdata/sample8.yaml"
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	c, err := LoadConfig(context.Background(), f, "339", path)
+	if err != nil {
+		t.Fatalf("error should be nil, but: %s", err)
+	}
+	ru := c.GetRuleByName("s3-object-created")
+	in := ru.PutRuleInput()
+	if in.ScheduleExpression != nil {
+		t.Errorf("scheduleExpression should be nil, but: %q", *in.ScheduleExpression)
+	}
+	e := `{"detail":{"bucket":{"name":["uploads"]},"object":{"size":[{"numeric":[">",0]}]}},"detail-type":["Object Created"],"source":["aws.s3"]}`
+	if g := aws.ToString(in.EventPattern); g != e {
+		t.Errorf("unexpected eventPattern\nwant: %s\ngot:  %s", e, g)
+	}
+
+	// the pattern reconstructed from the remote rule must be identical to the local one
+	remote, err := parseEventPattern(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := ru.localYAMLForDiff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lr := &Rule{Name: ru.Name}
+	if err := yaml.Unmarshal([]byte(local), lr); err != nil {
+		t.Fatal(err)
+	}
+	g, err := yaml.Marshal(lr.EventPattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := yaml.Marshal(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(g) != string(w) {
+		t.Errorf("unexpected eventPattern in diff\nwant:\n%s\ngot:\n%s", w, g)
+	}
+}
+
+func TestEventPatternValidate(t *testing.T) {
+	c := &Config{
+		Rules: []*Rule{
+			{Name: "rule-1", EventPattern: map[string]interface{}{"source": []interface{}{"aws.s3"}}}, // valid
+			{Name: "rule-2", ScheduleExpression: "rate(1 day)"},                                       // schedule only
+			{Name: "rule-3", ScheduleExpression: "rate(1 day)", EventPattern: map[string]interface{}{
+				"source": []interface{}{"aws.s3"},
+			}},
+			{Name: "rule-4", EventPattern: map[string]interface{}{}},
+			{Name: "rule-5", EventPattern: map[string]interface{}{
+				"detail": map[string]interface{}{"lastStatus": "STOPPED"},
+			}},
+		},
+	}
+	err := c.eventPatternValidate()
+	if err == nil {
+		t.Fatalf("error should be occurred, but nil")
+	}
+	e := "event pattern validation errors:\n" +
+		"\trule \"rule-3\": scheduleExpression and eventPattern are mutually exclusive\n" +
+		"\trule \"rule-4\": eventPattern must not be empty\n" +
+		"\trule \"rule-5\": invalid eventPattern: \"detail.lastStatus\" must be an array or an object"
+	if g := err.Error(); g != e {
+		t.Errorf("unexpected error message\nwant:\n%s\n\ngot:\n%s", e, g)
+	}
+}
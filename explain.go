@@ -0,0 +1,419 @@
+package ecschedule
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/winebarrel/cronplan"
+)
+
+// explainOptions configures the explanations of the schedule expressions. When loc is not nil,
+// the times are shown in loc as well, which are computed with the offsets at now.
+type explainOptions struct {
+	loc *time.Location
+	now time.Time
+}
+
+func newExplainOptions(tz string, now time.Time) (explainOptions, error) {
+	if tz == "" {
+		return explainOptions{}, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return explainOptions{}, fmt.Errorf("invalid timezone %q: %w", tz, err)
+	}
+	return explainOptions{loc: loc, now: now}, nil
+}
+
+// explainSchedule renders the schedule expression in English, e.g. "at 15:30 UTC, Monday through
+// Friday". Cron expressions are in timezone, or UTC when it is empty. When loc is not nil, the
+// times are shown in loc as well, which are computed with the offset at the next fire time from now.
+func explainSchedule(expr, timezone string, loc *time.Location, now time.Time) (string, error) {
+	s, err := parseSchedule(expr, timezone)
+	if err != nil {
+		return "", err
+	}
+	switch s := s.(type) {
+	case *rateSchedule:
+		return explainRate(s.interval), nil
+	case *atSchedule:
+		exp := "once at " + s.at.Format("2006-01-02 15:04 ") + zoneLabel(s.at.Location())
+		if loc != nil && loc.String() != s.at.Location().String() {
+			exp += " (" + s.at.In(loc).Format("2006-01-02 15:04 MST") + ")"
+		}
+		return exp, nil
+	case *cronSchedule:
+		exp := explainCron(s.exp, zoneLabel(s.loc))
+		if loc == nil || loc.String() == s.loc.String() {
+			return exp, nil
+		}
+		next := s.next(ceilMinute(now))
+		if next.IsZero() {
+			return exp, nil
+		}
+		if times := fixedTimes(s.exp); times != nil {
+			return exp + " (" + explainTimesIn(times, next, loc) + ")", nil
+		}
+		return exp + " (next at " + next.In(loc).Format("2006-01-02 15:04 MST") + ")", nil
+	}
+	return "", fmt.Errorf("unsupported expression: %q", expr)
+}
+
+func zoneLabel(loc *time.Location) string {
+	if loc == time.UTC {
+		return "UTC"
+	}
+	return loc.String()
+}
+
+func explainRate(d time.Duration) string {
+	var (
+		n    int
+		unit string
+	)
+	switch {
+	case d%(24*time.Hour) == 0:
+		n, unit = int(d/(24*time.Hour)), "day"
+	case d%time.Hour == 0:
+		n, unit = int(d/time.Hour), "hour"
+	default:
+		n, unit = int(d/time.Minute), "minute"
+	}
+	if n == 1 {
+		return "every " + unit
+	}
+	return fmt.Sprintf("every %d %ss", n, unit)
+}
+
+func explainCron(exp *cronplan.Expression, zone string) string {
+	var parts []string
+	if times := fixedTimes(exp); times != nil {
+		var hhmm []string
+		for _, t := range times {
+			hhmm = append(hhmm, fmt.Sprintf("%02d:%02d", t[0], t[1]))
+		}
+		parts = append(parts, "at "+joinWords(hhmm)+" "+zone)
+	} else {
+		parts = append(parts, explainMinutes(exp.Minute.Exps))
+		if p := explainHours(exp.Hour.Exps, zone); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	for _, p := range []string{
+		explainDaysOfMonth(exp.DayOfMonth),
+		explainDaysOfWeek(exp.DayOfWeek),
+		explainMonths(exp.Month.Exps),
+		explainYears(exp.Year.Exps),
+	} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// fixedTimes returns the pairs of the hour and the minute when the expression fires at fixed
+// times of the day, or nil otherwise
+func fixedTimes(exp *cronplan.Expression) [][2]int {
+	var hours, minutes []int
+	for _, e := range exp.Hour.Exps {
+		if e.Number == nil || e.Bottom != nil {
+			return nil
+		}
+		hours = append(hours, e.Number.Int())
+	}
+	for _, e := range exp.Minute.Exps {
+		if e.Number == nil || e.Bottom != nil {
+			return nil
+		}
+		minutes = append(minutes, e.Number.Int())
+	}
+	sort.Ints(hours)
+	sort.Ints(minutes)
+	var times [][2]int
+	for _, h := range hours {
+		for _, m := range minutes {
+			times = append(times, [2]int{h, m})
+		}
+	}
+	return times
+}
+
+// explainTimesIn renders the fixed times in loc on the date of next
+func explainTimesIn(times [][2]int, next time.Time, loc *time.Location) string {
+	var ss []string
+	for _, hm := range times {
+		t := time.Date(next.Year(), next.Month(), next.Day(), hm[0], hm[1], 0, 0, next.Location())
+		lt := t.In(loc)
+		s := lt.Format("15:04 MST")
+		switch lt.Format("2006-01-02") {
+		case t.AddDate(0, 0, 1).Format("2006-01-02"):
+			s += " the next day"
+		case t.AddDate(0, 0, -1).Format("2006-01-02"):
+			s += " the previous day"
+		}
+		ss = append(ss, s)
+	}
+	return joinWords(ss)
+}
+
+func explainMinutes(exps []*cronplan.MinuteExp) string {
+	var (
+		numbers []string
+		parts   []string
+	)
+	for _, e := range exps {
+		switch {
+		case e.Range != nil && e.Bottom != nil:
+			parts = append(parts, fmt.Sprintf("every %d minutes from minute %d through %d", *e.Bottom, e.Range.Start.Int(), e.Range.End.Int()))
+		case e.Range != nil:
+			parts = append(parts, fmt.Sprintf("every minute from minute %d through %d", e.Range.Start.Int(), e.Range.End.Int()))
+		case e.Bottom != nil:
+			p := fmt.Sprintf("every %d minutes", *e.Bottom)
+			if e.Number != nil && e.Number.Int() != 0 {
+				p += fmt.Sprintf(" starting at minute %d", e.Number.Int())
+			}
+			parts = append(parts, p)
+		case e.Wildcard:
+			parts = append(parts, "every minute")
+		default:
+			numbers = append(numbers, strconv.Itoa(e.Number.Int()))
+		}
+	}
+	if len(numbers) == 1 {
+		parts = append(parts, "at minute "+numbers[0])
+	} else if len(numbers) > 1 {
+		parts = append(parts, "at minutes "+joinWords(numbers))
+	}
+	return joinWords(parts)
+}
+
+func explainHours(exps []*cronplan.HourExp, zone string) string {
+	var (
+		numbers []string
+		parts   []string
+	)
+	for _, e := range exps {
+		switch {
+		case e.Range != nil:
+			p := fmt.Sprintf("between %02d:00 and %02d:59 %s", e.Range.Start.Int(), e.Range.End.Int(), zone)
+			if e.Bottom != nil {
+				p = fmt.Sprintf("every %d hours ", *e.Bottom) + p
+			}
+			parts = append(parts, p)
+		case e.Bottom != nil:
+			p := fmt.Sprintf("every %d hours", *e.Bottom)
+			if e.Number != nil && e.Number.Int() != 0 {
+				p += fmt.Sprintf(" starting at %02d:00 %s", e.Number.Int(), zone)
+			}
+			parts = append(parts, p)
+		case e.Wildcard:
+			// every hour
+		default:
+			numbers = append(numbers, fmt.Sprintf("%02d:00", e.Number.Int()))
+		}
+	}
+	if len(numbers) == 1 {
+		parts = append(parts, fmt.Sprintf("during the %s hour %s", numbers[0], zone))
+	} else if len(numbers) > 1 {
+		parts = append(parts, fmt.Sprintf("during the %s hours %s", joinWords(numbers), zone))
+	}
+	return joinWords(parts)
+}
+
+func explainDaysOfMonth(f *cronplan.DayOfMonthField) string {
+	if f.Any {
+		return ""
+	}
+	var (
+		numbers []string
+		parts   []string
+	)
+	for _, e := range f.Exps {
+		switch {
+		case e.NearestWeekday != nil:
+			parts = append(parts, fmt.Sprintf("on the weekday nearest day %d of the month", e.NearestWeekday.Int()))
+		case e.LastWeekday != nil:
+			parts = append(parts, "on the last weekday of the month")
+		case e.Last != nil && e.Last.Int() == 0:
+			parts = append(parts, "on the last day of the month")
+		case e.Last != nil:
+			parts = append(parts, fmt.Sprintf("%d %s before the last day of the month", e.Last.Int(), plural(e.Last.Int(), "day")))
+		case e.Range != nil && e.Bottom != nil:
+			parts = append(parts, fmt.Sprintf("every %d days from day %d through %d of the month", *e.Bottom, e.Range.Start.Int(), e.Range.End.Int()))
+		case e.Range != nil:
+			parts = append(parts, fmt.Sprintf("on days %d through %d of the month", e.Range.Start.Int(), e.Range.End.Int()))
+		case e.Bottom != nil:
+			start := 1
+			if e.Number != nil {
+				start = e.Number.Int()
+			}
+			parts = append(parts, fmt.Sprintf("every %d days of the month starting on day %d", *e.Bottom, start))
+		case e.Wildcard:
+			// every day
+		default:
+			numbers = append(numbers, strconv.Itoa(e.Number.Int()))
+		}
+	}
+	if len(numbers) > 0 {
+		parts = append(parts, fmt.Sprintf("on %s %s of the month", plural(len(numbers), "day"), joinWords(numbers)))
+	}
+	return joinWords(parts)
+}
+
+var ordinals = []string{"", "first", "second", "third", "fourth", "fifth"}
+
+func explainDaysOfWeek(f *cronplan.DayOfWeekField) string {
+	if f.Any {
+		return ""
+	}
+	var (
+		names []string
+		parts []string
+	)
+	for _, e := range f.Exps {
+		switch {
+		case e.Nth != nil:
+			nth := strconv.Itoa(e.Nth.Nth) + "th"
+			if e.Nth.Nth < len(ordinals) {
+				nth = ordinals[e.Nth.Nth]
+			}
+			parts = append(parts, fmt.Sprintf("on the %s %s of the month", nth, e.Nth.Wday.Weekday()))
+		case e.Last != nil && e.Last.Wday != nil:
+			parts = append(parts, fmt.Sprintf("on the last %s of the month", e.Last.Weekday()))
+		case e.Last != nil:
+			names = append(names, time.Saturday.String())
+		case e.Range != nil && e.Bottom != nil:
+			parts = append(parts, fmt.Sprintf("every %d days from %s through %s", *e.Bottom, e.Range.Start.Weekday(), e.Range.End.Weekday()))
+		case e.Range != nil:
+			parts = append(parts, fmt.Sprintf("%s through %s", e.Range.Start.Weekday(), e.Range.End.Weekday()))
+		case e.Bottom != nil:
+			start := time.Sunday
+			if e.Wday != nil {
+				start = e.Wday.Weekday()
+			}
+			parts = append(parts, fmt.Sprintf("every %d days of the week starting on %s", *e.Bottom, start))
+		case e.Wildcard:
+			// every day
+		default:
+			names = append(names, e.Wday.Weekday().String())
+		}
+	}
+	if len(names) > 0 {
+		parts = append(parts, "on "+joinWords(names))
+	}
+	return joinWords(parts)
+}
+
+func explainMonths(exps []*cronplan.MonthExp) string {
+	var (
+		names []string
+		parts []string
+	)
+	for _, e := range exps {
+		switch {
+		case e.Range != nil && e.Bottom != nil:
+			parts = append(parts, fmt.Sprintf("every %d months from %s through %s", *e.Bottom, e.Range.Start.Month(), e.Range.End.Month()))
+		case e.Range != nil:
+			parts = append(parts, fmt.Sprintf("%s through %s", e.Range.Start.Month(), e.Range.End.Month()))
+		case e.Bottom != nil:
+			start := time.January
+			if e.Month != nil {
+				start = e.Month.Month()
+			}
+			parts = append(parts, fmt.Sprintf("every %d months starting in %s", *e.Bottom, start))
+		case e.Wildcard:
+			// every month
+		default:
+			names = append(names, e.Month.Month().String())
+		}
+	}
+	if len(names) > 0 {
+		parts = append(parts, "in "+joinWords(names))
+	}
+	return joinWords(parts)
+}
+
+func explainYears(exps []*cronplan.YearExp) string {
+	var (
+		numbers []string
+		parts   []string
+	)
+	for _, e := range exps {
+		switch {
+		case e.Range != nil && e.Bottom != nil:
+			parts = append(parts, fmt.Sprintf("every %d years from %d through %d", *e.Bottom, e.Range.Start.Int(), e.Range.End.Int()))
+		case e.Range != nil:
+			parts = append(parts, fmt.Sprintf("from %d through %d", e.Range.Start.Int(), e.Range.End.Int()))
+		case e.Bottom != nil:
+			p := fmt.Sprintf("every %d years", *e.Bottom)
+			if e.Number != nil {
+				p += fmt.Sprintf(" starting in %d", e.Number.Int())
+			}
+			parts = append(parts, p)
+		case e.Wildcard:
+			// every year
+		default:
+			numbers = append(numbers, strconv.Itoa(e.Number.Int()))
+		}
+	}
+	if len(numbers) > 0 {
+		parts = append(parts, "in "+joinWords(numbers))
+	}
+	return joinWords(parts)
+}
+
+// joinWords joins the words in English, e.g. "a, b and c"
+func joinWords(words []string) string {
+	switch len(words) {
+	case 0:
+		return ""
+	case 1:
+		return words[0]
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " and " + words[len(words)-1]
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+var scheduleExpressionLine = regexp.MustCompile(`^(\s*(?:- )?)scheduleExpression: (.+)$`)
+
+// annotateSchedules appends the explanations of the schedule expressions in the YAML of the
+// rules as comments. The timezone of the expression is read from scheduleExpressionTimezone of
+// the same rule.
+func annotateSchedules(yml string, eo explainOptions) string {
+	lines := strings.Split(yml, "\n")
+	for i, line := range lines {
+		m := scheduleExpressionLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		indent := strings.Repeat(" ", len(m[1]))
+		var timezone string
+		for _, l := range lines[i+1:] {
+			if !strings.HasPrefix(l, indent) {
+				// the next rule
+				break
+			}
+			if tz, ok := strings.CutPrefix(l, indent+"scheduleExpressionTimezone: "); ok {
+				timezone = tz
+				break
+			}
+		}
+		exp, err := explainSchedule(m[2], timezone, eo.loc, eo.now)
+		if err != nil {
+			continue
+		}
+		lines[i] = line + " # " + exp
+	}
+	return strings.Join(lines, "\n")
+}
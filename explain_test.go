@@ -0,0 +1,126 @@
+package ecschedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExplainSchedule(t *testing.T) {
+	testCases := []struct {
+		expr string
+		want string
+	}{
+		{"cron(30 15 ? * MON-FRI *)", "at 15:30 UTC, Monday through Friday"},
+		{"cron(0 9 * * ? *)", "at 09:00 UTC"},
+		{"cron(0 9,18 * * ? *)", "at 09:00 and 18:00 UTC"},
+		{"cron(0/15 * * * ? *)", "every 15 minutes"},
+		{"cron(5/10 9-17 ? * MON,WED,FRI *)", "every 10 minutes starting at minute 5, between 09:00 and 17:59 UTC, on Monday, Wednesday and Friday"},
+		{"cron(* 3 * * ? *)", "every minute, during the 03:00 hour UTC"},
+		{"cron(0 */2 * * ? *)", "at minute 0, every 2 hours"},
+		{"cron(0 0 1,15 * ? *)", "at 00:00 UTC, on days 1 and 15 of the month"},
+		{"cron(0 0 L * ? *)", "at 00:00 UTC, on the last day of the month"},
+		{"cron(0 0 L-2 * ? *)", "at 00:00 UTC, 2 days before the last day of the month"},
+		{"cron(0 0 15W * ? *)", "at 00:00 UTC, on the weekday nearest day 15 of the month"},
+		{"cron(0 0 LW * ? *)", "at 00:00 UTC, on the last weekday of the month"},
+		{"cron(0 12 ? * MON#2 *)", "at 12:00 UTC, on the second Monday of the month"},
+		{"cron(0 12 ? * 6L *)", "at 12:00 UTC, on the last Friday of the month"},
+		{"cron(0 0 1 JAN,JUL ? 2027)", "at 00:00 UTC, on day 1 of the month, in January and July, in 2027"},
+		{"cron(0 0 ? 3-5 SUN 2026-2028)", "at 00:00 UTC, on Sunday, March through May, from 2026 through 2028"},
+		{"rate(1 minute)", "every minute"},
+		{"rate(5 minutes)", "every 5 minutes"},
+		{"rate(12 hours)", "every 12 hours"},
+		{"rate(1 day)", "every day"},
+		{"at(2026-01-05T10:00:00)", "once at 2026-01-05 10:00 UTC"},
+	}
+	now := mustTime(t, "2026-01-01T00:00:00Z")
+	for _, tc := range testCases {
+		got, err := explainSchedule(tc.expr, "", nil, now)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.expr, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s:\n got %q\nwant %q", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestExplainSchedule_timezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		expr, timezone string
+		loc            *time.Location
+		now            string
+		want           string
+	}{
+		{"cron(30 15 ? * MON-FRI *)", "", tokyo, "2026-01-01T00:00:00Z",
+			"at 15:30 UTC, Monday through Friday (00:30 JST the next day)"},
+		{"cron(0 2,14 * * ? *)", "", newYork, "2026-01-01T00:00:00Z",
+			"at 02:00 and 14:00 UTC (21:00 EST the previous day and 09:00 EST)"},
+		{"cron(0 14 * * ? *)", "", newYork, "2026-07-01T00:00:00Z",
+			"at 14:00 UTC (10:00 EDT)"},
+		{"cron(0/15 * * * ? *)", "", tokyo, "2026-01-01T00:07:00Z",
+			"every 15 minutes (next at 2026-01-01 09:15 JST)"},
+		{"cron(0 9 * * ? *)", "Asia/Tokyo", nil, "2026-01-01T00:00:00Z",
+			"at 09:00 Asia/Tokyo"},
+		{"cron(0 9 * * ? *)", "Asia/Tokyo", time.UTC, "2026-01-01T00:00:00Z",
+			"at 09:00 Asia/Tokyo (00:00 UTC)"},
+	}
+	for _, tc := range testCases {
+		got, err := explainSchedule(tc.expr, tc.timezone, tc.loc, mustTime(t, tc.now))
+		if err != nil {
+			t.Fatalf("%s: %s", tc.expr, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s:\n got %q\nwant %q", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestAnnotateSchedules(t *testing.T) {
+	in := `rules:
+- name: hoge
+  scheduleExpression: cron(0 9 * * ? *)
+  scheduleExpressionTimezone: Asia/Tokyo
+  taskDefinition: hoge
+- name: fuga
+  scheduleExpression: rate(5 minutes)
+  taskDefinition: fuga
+`
+	want := `rules:
+- name: hoge
+  scheduleExpression: cron(0 9 * * ? *) # at 09:00 Asia/Tokyo
+  scheduleExpressionTimezone: Asia/Tokyo
+  taskDefinition: hoge
+- name: fuga
+  scheduleExpression: rate(5 minutes) # every 5 minutes
+  taskDefinition: fuga
+`
+	if got := annotateSchedules(in, explainOptions{}); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	// a single rule as in diff
+	got := annotateSchedules("name: hoge\nscheduleExpression: cron(30 15 ? * MON-FRI *)\n", explainOptions{})
+	if !strings.Contains(got, "scheduleExpression: cron(30 15 ? * MON-FRI *) # at 15:30 UTC, Monday through Friday\n") {
+		t.Errorf("unexpected annotation: %s", got)
+	}
+	// with -explain-timezone
+	eo, err := newExplainOptions("Asia/Tokyo", mustTime(t, "2026-01-01T00:00:00Z"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = annotateSchedules("name: hoge\nscheduleExpression: cron(30 15 ? * MON-FRI *)\n", eo)
+	if !strings.Contains(got, "# at 15:30 UTC, Monday through Friday (00:30 JST the next day)\n") {
+		t.Errorf("unexpected annotation: %s", got)
+	}
+	if _, err := newExplainOptions("Mars/Olympus", time.Time{}); err == nil {
+		t.Error("error should be occurred for an invalid timezone")
+	}
+}
@@ -0,0 +1,234 @@
+package ecschedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/winebarrel/cronplan"
+)
+
+const icsTimeFormat = "20060102T150405Z"
+
+// icsWriter builds an iCalendar (RFC 5545) feed of the schedules of the rules
+type icsWriter struct {
+	buf      strings.Builder
+	uidHost  string
+	now      time.Time
+	from, to time.Time
+}
+
+// exportICS renders the rules as VEVENTs. The schedules which can be expressed by RRULE recur
+// from the first fire time after from, and the others are expanded into the occurrences in
+// [from, to).
+func exportICS(name string, rules []*Rule, now, from, to time.Time) (string, error) {
+	w := &icsWriter{uidHost: name + ".ecschedule", now: now.UTC(), from: from, to: to}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//Songmu//ecschedule//EN")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("X-WR-CALNAME:" + icsEscape("ecschedule: "+name))
+	for _, r := range rules {
+		if err := w.rule(r); err != nil {
+			return "", fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+	}
+	w.line("END:VCALENDAR")
+	return w.buf.String(), nil
+}
+
+func (w *icsWriter) rule(r *Rule) error {
+	s, err := r.schedule()
+	if err != nil || s == nil {
+		return err
+	}
+	d, err := r.expectedDuration()
+	if err != nil {
+		return err
+	}
+	if d < minExecutionDuration {
+		d = minExecutionDuration
+	}
+	first := nextFireTimes(s, w.from, 1)
+	if len(first) == 0 {
+		// never fires again
+		return nil
+	}
+	if rrule := scheduleRRule(s); rrule != "" {
+		w.event(r, r.Name, first[0], d, rrule)
+		return nil
+	}
+	if _, ok := s.(*atSchedule); ok {
+		w.event(r, r.Name, first[0], d, "")
+		return nil
+	}
+	for _, t := range fireTimesBetween(s, w.from, w.to) {
+		w.event(r, r.Name+"-"+t.UTC().Format(icsTimeFormat), t, d, "")
+	}
+	return nil
+}
+
+func (w *icsWriter) event(r *Rule, uid string, start time.Time, d time.Duration, rrule string) {
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + uid + "@" + w.uidHost)
+	w.line("DTSTAMP:" + w.now.Format(icsTimeFormat))
+	w.line("DTSTART:" + start.UTC().Format(icsTimeFormat))
+	w.line("DURATION:" + icsDuration(d))
+	if rrule != "" {
+		w.line("RRULE:" + rrule)
+	}
+	w.line("SUMMARY:" + icsEscape(r.Name))
+	var desc []string
+	if r.Description != "" {
+		desc = append(desc, r.Description)
+	}
+	for _, ta := range r.targets() {
+		desc = append(desc, "Task definition: "+ta.TaskDefinition)
+	}
+	desc = append(desc, "Schedule: "+r.ScheduleExpression)
+	w.line("DESCRIPTION:" + icsEscape(strings.Join(desc, "\n")))
+	if r.Disabled {
+		w.line("STATUS:CANCELLED")
+	} else {
+		w.line("STATUS:CONFIRMED")
+	}
+	w.line("END:VEVENT")
+}
+
+// line writes the content line folded at 75 octets
+func (w *icsWriter) line(s string) {
+	for len(s) > 75 {
+		i := 75
+		// don't split a multibyte character
+		for i > 0 && s[i]&0xC0 == 0x80 {
+			i--
+		}
+		w.buf.WriteString(s[:i] + "\r\n")
+		s = " " + s[i:]
+	}
+	w.buf.WriteString(s + "\r\n")
+}
+
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+func icsDuration(d time.Duration) string {
+	if d%time.Minute == 0 {
+		return fmt.Sprintf("PT%dM", d/time.Minute)
+	}
+	return fmt.Sprintf("PT%dS", (d+time.Second-1)/time.Second)
+}
+
+// scheduleRRule returns the RRULE of the schedule, or "" when it can't be expressed by RRULE
+func scheduleRRule(s schedule) string {
+	switch s := s.(type) {
+	case *rateSchedule:
+		switch {
+		case s.interval%(24*time.Hour) == 0:
+			return fmt.Sprintf("FREQ=DAILY;INTERVAL=%d", s.interval/(24*time.Hour))
+		case s.interval%time.Hour == 0:
+			return fmt.Sprintf("FREQ=HOURLY;INTERVAL=%d", s.interval/time.Hour)
+		}
+		return fmt.Sprintf("FREQ=MINUTELY;INTERVAL=%d", s.interval/time.Minute)
+	case *cronSchedule:
+		if s.loc != time.UTC {
+			// DTSTART is in UTC, so the recurrence would drift across DST
+			return ""
+		}
+		return cronRRule(s.exp)
+	}
+	return ""
+}
+
+var icsWeekdays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// cronRRule converts the cron expression into RRULE. The fields are expanded by matching the
+// candidate values, since the ranges and the steps of cron are lists in RRULE. It returns ""
+// for W, LW and years, which RRULE can't express.
+func cronRRule(exp *cronplan.Expression) string {
+	for _, e := range exp.Year.Exps {
+		if !e.Wildcard || e.Bottom != nil {
+			return ""
+		}
+	}
+	var (
+		parts []string
+		freq  = "DAILY"
+	)
+
+	var months []string
+	for m := 1; m <= 12; m++ {
+		if exp.Month.Match(time.Date(2000, time.Month(m), 1, 0, 0, 0, 0, time.UTC)) {
+			months = append(months, strconv.Itoa(m))
+		}
+	}
+	if len(months) < 12 {
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+
+	if !exp.DayOfMonth.Any {
+		var days []string
+		for _, e := range exp.DayOfMonth.Exps {
+			switch {
+			case e.NearestWeekday != nil, e.LastWeekday != nil:
+				return ""
+			case e.Last != nil:
+				days = append(days, strconv.Itoa(-1-e.Last.Int()))
+			case e.Range != nil && e.Range.Start.Int() > e.Range.End.Int():
+				// wraps around the end of the month
+				return ""
+			default:
+				for d := 1; d <= 31; d++ {
+					if e.Match(time.Date(2000, time.January, d, 0, 0, 0, 0, time.UTC)) {
+						days = append(days, strconv.Itoa(d))
+					}
+				}
+			}
+		}
+		if len(days) < 31 {
+			parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+		}
+	}
+
+	if !exp.DayOfWeek.Any {
+		var wdays []string
+		for _, e := range exp.DayOfWeek.Exps {
+			switch {
+			case e.Nth != nil:
+				freq = "MONTHLY"
+				wdays = append(wdays, strconv.Itoa(e.Nth.Nth)+icsWeekdays[e.Nth.Wday.Int()])
+			case e.Last != nil && e.Last.Wday != nil:
+				freq = "MONTHLY"
+				wdays = append(wdays, "-1"+icsWeekdays[e.Last.Wday.Int()])
+			default:
+				// 2000-01-02 is Sunday
+				for i := 0; i < 7; i++ {
+					if e.Match(time.Date(2000, time.January, 2+i, 0, 0, 0, 0, time.UTC)) {
+						wdays = append(wdays, icsWeekdays[i])
+					}
+				}
+			}
+		}
+		if len(wdays) < 7 || freq == "MONTHLY" {
+			parts = append(parts, "BYDAY="+strings.Join(wdays, ","))
+		}
+	}
+
+	var hours []string
+	for h := 0; h < 24; h++ {
+		if exp.Hour.Match(time.Date(2000, time.January, 1, h, 0, 0, 0, time.UTC)) {
+			hours = append(hours, strconv.Itoa(h))
+		}
+	}
+	parts = append(parts, "BYHOUR="+strings.Join(hours, ","))
+	var minutes []string
+	for m := 0; m < 60; m++ {
+		if exp.Minute.Match(time.Date(2000, time.January, 1, 0, m, 0, 0, time.UTC)) {
+			minutes = append(minutes, strconv.Itoa(m))
+		}
+	}
+	parts = append(parts, "BYMINUTE="+strings.Join(minutes, ","))
+	return strings.Join(append([]string{"FREQ=" + freq}, parts...), ";")
+}
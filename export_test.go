@@ -0,0 +1,109 @@
+package ecschedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduleRRule(t *testing.T) {
+	testCases := []struct {
+		expr     string
+		timezone string
+		want     string
+	}{
+		{"cron(0 9 * * ? *)", "", "FREQ=DAILY;BYHOUR=9;BYMINUTE=0"},
+		{"cron(30 15 ? * MON-FRI *)", "", "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;BYHOUR=15;BYMINUTE=30"},
+		{"cron(0/20 8-10 ? * 1 *)", "", "FREQ=DAILY;BYDAY=SU;BYHOUR=8,9,10;BYMINUTE=0,20,40"},
+		{"cron(0 0 1,15 JAN,JUL ? *)", "", "FREQ=DAILY;BYMONTH=1,7;BYMONTHDAY=1,15;BYHOUR=0;BYMINUTE=0"},
+		{"cron(0 0 L * ? *)", "", "FREQ=DAILY;BYMONTHDAY=-1;BYHOUR=0;BYMINUTE=0"},
+		{"cron(0 0 L-2 * ? *)", "", "FREQ=DAILY;BYMONTHDAY=-3;BYHOUR=0;BYMINUTE=0"},
+		{"cron(0 12 ? * MON#2 *)", "", "FREQ=MONTHLY;BYDAY=2MO;BYHOUR=12;BYMINUTE=0"},
+		{"cron(0 12 ? * 6L *)", "", "FREQ=MONTHLY;BYDAY=-1FR;BYHOUR=12;BYMINUTE=0"},
+		{"rate(5 minutes)", "", "FREQ=MINUTELY;INTERVAL=5"},
+		{"rate(2 hours)", "", "FREQ=HOURLY;INTERVAL=2"},
+		{"rate(1 day)", "", "FREQ=DAILY;INTERVAL=1"},
+		// not expressible
+		{"cron(0 0 15W * ? *)", "", ""},
+		{"cron(0 0 LW * ? *)", "", ""},
+		{"cron(0 0 1 * ? 2027)", "", ""},
+		{"cron(0 9 * * ? *)", "Asia/Tokyo", ""},
+		{"at(2026-01-05T10:00:00)", "", ""},
+	}
+	for _, tc := range testCases {
+		s, err := parseSchedule(tc.expr, tc.timezone)
+		if err != nil {
+			t.Fatalf("%s: %s", tc.expr, err)
+		}
+		if got := scheduleRRule(s); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestExportICS(t *testing.T) {
+	rules := []*Rule{
+		{
+			Name:               "daily-report",
+			Description:        "report, for the sales; daily",
+			ScheduleExpression: "cron(0 9 * * ? *)",
+			ExpectedDuration:   "15m",
+			Target:             &Target{TaskDefinition: "report:3"},
+		},
+		{
+			Name:               "month-end",
+			ScheduleExpression: "cron(0 0 LW * ? *)",
+			Disabled:           true,
+			Target:             &Target{TaskDefinition: "batch"},
+		},
+		{
+			Name:         "on-event",
+			EventPattern: map[string]interface{}{"source": []string{"aws.ecs"}},
+			Target:       &Target{TaskDefinition: "hook"},
+		},
+	}
+	now := mustTime(t, "2026-01-10T12:34:56Z")
+	out, err := exportICS("api", rules, now, now, now.Add(60*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.ReplaceAll(`BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Songmu//ecschedule//EN
+CALSCALE:GREGORIAN
+X-WR-CALNAME:ecschedule: api
+BEGIN:VEVENT
+UID:daily-report@api.ecschedule
+DTSTAMP:20260110T123456Z
+DTSTART:20260111T090000Z
+DURATION:PT15M
+RRULE:FREQ=DAILY;BYHOUR=9;BYMINUTE=0
+SUMMARY:daily-report
+DESCRIPTION:report\, for the sales\; daily\nTask definition: report:3\nSche
+ dule: cron(0 9 * * ? *)
+STATUS:CONFIRMED
+END:VEVENT
+BEGIN:VEVENT
+UID:month-end-20260130T000000Z@api.ecschedule
+DTSTAMP:20260110T123456Z
+DTSTART:20260130T000000Z
+DURATION:PT1M
+SUMMARY:month-end
+DESCRIPTION:Task definition: batch\nSchedule: cron(0 0 LW * ? *)
+STATUS:CANCELLED
+END:VEVENT
+BEGIN:VEVENT
+UID:month-end-20260227T000000Z@api.ecschedule
+DTSTAMP:20260110T123456Z
+DTSTART:20260227T000000Z
+DURATION:PT1M
+SUMMARY:month-end
+DESCRIPTION:Task definition: batch\nSchedule: cron(0 0 LW * ? *)
+STATUS:CANCELLED
+END:VEVENT
+END:VCALENDAR
+`, "\n", "\r\n")
+	if out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
@@ -0,0 +1,161 @@
+package ecschedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logsTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// logsClient is the subset of the CloudWatch Logs API used by run -follow.
+// It is replaced with a fake in tests.
+type logsClient interface {
+	GetLogEvents(context.Context, *cloudwatchlogs.GetLogEventsInput, ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetLogEventsOutput, error)
+}
+
+// logPollInterval is the interval to poll the log streams followed by run
+var logPollInterval = 2 * time.Second
+
+// logStream is a CloudWatch Logs stream of a container of a running task
+type logStream struct {
+	prefix string
+	group  string
+	name   string
+}
+
+// taskLogStreams returns the log streams of the containers of the tasks using the awslogs log driver.
+// The stream name of a container is "<awslogs-stream-prefix>/<container name>/<task id>".
+// Containers without a stream prefix are skipped because the stream name can't be derived.
+func taskLogStreams(ctx context.Context, svc ecsClient, tasks []runningTask, taskDefs map[string]string) ([]logStream, error) {
+	containerDefs := map[string][]ecsTypes.ContainerDefinition{}
+	var streams []logStream
+	for _, t := range tasks {
+		taskDef := taskDefs[t.arn]
+		if _, ok := containerDefs[taskDef]; !ok {
+			out, err := svc.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+				TaskDefinition: aws.String(taskDef),
+			})
+			if err != nil {
+				return nil, err
+			}
+			containerDefs[taskDef] = out.TaskDefinition.ContainerDefinitions
+		}
+		for _, cd := range containerDefs[taskDef] {
+			name := aws.ToString(cd.Name)
+			lc := cd.LogConfiguration
+			if lc == nil || lc.LogDriver != ecsTypes.LogDriverAwslogs {
+				log.Printf("the container %q doesn't use the awslogs log driver. skip following its logs", name)
+				continue
+			}
+			group, prefix := lc.Options["awslogs-group"], lc.Options["awslogs-stream-prefix"]
+			if prefix == "" {
+				log.Printf("the container %q has no awslogs-stream-prefix. skip following its logs", name)
+				continue
+			}
+			label := name
+			if len(tasks) > 1 {
+				label = name + ":" + t.id()
+			}
+			streams = append(streams, logStream{
+				prefix: "[" + label + "] ",
+				group:  group,
+				name:   strings.Join([]string{prefix, name, t.id()}, "/"),
+			})
+		}
+	}
+	return streams, nil
+}
+
+// logFollower writes the events of the log streams to out until stopped is closed
+type logFollower struct {
+	svc     logsClient
+	out     io.Writer
+	stopped chan struct{}
+
+	mu sync.Mutex
+	wg sync.WaitGroup
+}
+
+func newLogFollower(svc logsClient, out io.Writer) *logFollower {
+	return &logFollower{svc: svc, out: out, stopped: make(chan struct{})}
+}
+
+func (lf *logFollower) follow(ctx context.Context, streams []logStream) {
+	for _, s := range streams {
+		lf.wg.Add(1)
+		go func() {
+			defer lf.wg.Done()
+			if err := lf.followStream(ctx, s); err != nil && !errors.Is(err, context.Canceled) {
+				log.Printf("failed to follow the log stream %q: %s", s.name, err)
+			}
+		}()
+	}
+}
+
+// stop lets the followers read the remaining events and waits for them to finish
+func (lf *logFollower) stop() {
+	close(lf.stopped)
+	lf.wg.Wait()
+}
+
+func (lf *logFollower) followStream(ctx context.Context, s logStream) error {
+	var (
+		token    *string
+		finished bool
+	)
+	for {
+		// poll once more after the tasks stopped to read the remaining events
+		drain := finished
+		out, err := lf.svc.GetLogEvents(ctx, &cloudwatchlogs.GetLogEventsInput{
+			LogGroupName:  aws.String(s.group),
+			LogStreamName: aws.String(s.name),
+			NextToken:     token,
+			StartFromHead: aws.Bool(true),
+		})
+		var idle bool
+		if err != nil {
+			// the log stream is not created until the container starts
+			var nfe *logsTypes.ResourceNotFoundException
+			if !errors.As(err, &nfe) {
+				return err
+			}
+			idle = true
+		} else {
+			lf.write(s, out.Events)
+			// the same token is returned when there are no more events
+			idle = token != nil && aws.ToString(out.NextForwardToken) == aws.ToString(token)
+			token = out.NextForwardToken
+		}
+		if !idle {
+			continue
+		}
+		if drain {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-lf.stopped:
+			finished = true
+		case <-time.After(logPollInterval):
+		}
+	}
+}
+
+func (lf *logFollower) write(s logStream, events []logsTypes.OutputLogEvent) {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	for _, ev := range events {
+		fmt.Fprintln(lf.out, s.prefix+strings.TrimRight(aws.ToString(ev.Message), "\n"))
+	}
+}
@@ -0,0 +1,97 @@
+package ecschedule
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logsTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// fakeLogs is a fake CloudWatch Logs client. Each GetLogEvents call returns the next event of the stream.
+type fakeLogs struct {
+	mu     sync.Mutex
+	events map[string][]string // by "<group>:<stream>"
+}
+
+func (f *fakeLogs) GetLogEvents(_ context.Context, in *cloudwatchlogs.GetLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetLogEventsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	evs, ok := f.events[aws.ToString(in.LogGroupName)+":"+aws.ToString(in.LogStreamName)]
+	if !ok {
+		return nil, &logsTypes.ResourceNotFoundException{Message: aws.String("The specified log stream does not exist.")}
+	}
+	var pos int
+	if in.NextToken != nil {
+		pos = len(*in.NextToken)
+	}
+	out := &cloudwatchlogs.GetLogEventsOutput{NextForwardToken: aws.String(strings.Repeat("f", pos))}
+	if pos < len(evs) {
+		out.Events = []logsTypes.OutputLogEvent{{Message: aws.String(evs[pos] + "\n")}}
+		out.NextForwardToken = aws.String(strings.Repeat("f", pos+1))
+	}
+	return out, nil
+}
+
+func TestRule_run_follow(t *testing.T) {
+	defer func(d, l time.Duration) { taskPollInterval, logPollInterval = d, l }(taskPollInterval, logPollInterval)
+	taskPollInterval, logPollInterval = time.Millisecond, time.Millisecond
+
+	svc := &fakeECS{
+		polls:     2,
+		exitCodes: map[string]*int32{"app": aws.Int32(0), "sidecar": aws.Int32(0)},
+		essential: map[string]bool{"app": true, "sidecar": false},
+		awslogs:   true,
+	}
+	logs := &fakeLogs{events: map[string][]string{
+		"/ecs/task1:ecs/app/task1-0":     {"hello", "world"},
+		"/ecs/task1:ecs/app/task1-1":     {"hello"},
+		"/ecs/task1:ecs/sidecar/task1-0": {"ready"},
+		// the stream of the sidecar of task1-1 is not created
+	}}
+	out := &bytes.Buffer{}
+	if _, err := testRunRule().run(context.Background(), svc, logs, &RunOptions{Follow: true, Out: out}); err != nil {
+		t.Fatalf("error should be nil, but: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	sort.Strings(lines)
+	expect := []string{
+		"[app:task1-0] hello",
+		"[app:task1-0] world",
+		"[app:task1-1] hello",
+		"[sidecar:task1-0] ready",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestTaskLogStreams(t *testing.T) {
+	svc := &fakeECS{essential: map[string]bool{"app": true}, awslogs: true}
+	task := runningTask{cluster: "api", arn: "arn:aws:ecs:us-east-1:339:task/api/0123abcd"}
+	streams, err := taskLogStreams(context.Background(), svc, []runningTask{task}, map[string]string{
+		task.arn: "arn:aws:ecs:us-east-1:339:task-definition/task1:1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := logStream{prefix: "[app] ", group: "/ecs/task1", name: "ecs/app/0123abcd"}
+	if len(streams) != 1 || streams[0] != expect {
+		t.Errorf("unexpected streams: %+v", streams)
+	}
+
+	svc.awslogs = false
+	streams, err = taskLogStreams(context.Background(), svc, []runningTask{task}, map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(streams) != 0 {
+		t.Errorf("containers without awslogs should be skipped, but: %+v", streams)
+	}
+}
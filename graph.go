@@ -0,0 +1,162 @@
+package ecschedule
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+type graphBar struct {
+	start, end time.Time
+}
+
+// graphRow is a row of the timeline, which has the bars of the executions of a rule
+type graphRow struct {
+	name     string
+	disabled bool
+	bars     []graphBar
+}
+
+type graph struct {
+	title    string
+	from, to time.Time
+	rows     []*graphRow
+}
+
+// newGraph expands the schedules of the rules in [from, to). The bars last for expectedDuration
+// of the rules and are clipped at to.
+func newGraph(title string, rules []*Rule, from, to time.Time) (*graph, error) {
+	g := &graph{title: title, from: from.UTC(), to: to.UTC()}
+	for _, r := range rules {
+		s, err := r.schedule()
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+		if s == nil {
+			// triggered by events
+			continue
+		}
+		d, err := r.expectedDuration()
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+		if d < minExecutionDuration {
+			d = minExecutionDuration
+		}
+		row := &graphRow{name: r.Name, disabled: r.Disabled}
+		for _, t := range fireTimesBetween(s, from, to) {
+			end := t.Add(d)
+			if end.After(to) {
+				end = to
+			}
+			row.bars = append(row.bars, graphBar{start: t.UTC(), end: end.UTC()})
+		}
+		g.rows = append(g.rows, row)
+	}
+	return g, nil
+}
+
+func (g *graph) span() time.Duration {
+	return g.to.Sub(g.from)
+}
+
+// mermaid renders the timeline as a Mermaid gantt chart. Disabled rules are shown as done.
+func (g *graph) mermaid() string {
+	var buf strings.Builder
+	axis := "%H:%M"
+	if g.span() > 24*time.Hour {
+		axis = "%m-%d %H:%M"
+	}
+	fmt.Fprintln(&buf, "gantt")
+	fmt.Fprintf(&buf, "    title %s (%s - %s UTC)\n", g.title, g.from.Format("2006-01-02 15:04"), g.to.Format("2006-01-02 15:04"))
+	fmt.Fprintln(&buf, "    dateFormat YYYY-MM-DD HH:mm")
+	fmt.Fprintf(&buf, "    axisFormat %s\n", axis)
+	fmt.Fprintln(&buf, "    todayMarker off")
+	for _, row := range g.rows {
+		fmt.Fprintf(&buf, "    section %s\n", row.name)
+		var tag string
+		if row.disabled {
+			tag = "done, "
+		}
+		for _, b := range row.bars {
+			fmt.Fprintf(&buf, "    %s :%s%s, %dm\n", row.name, tag, b.start.Format("2006-01-02 15:04"), int(b.end.Sub(b.start)/time.Minute))
+		}
+	}
+	return buf.String()
+}
+
+// layout of the SVG
+const (
+	graphWidth      = 1200
+	graphLabelWidth = 240
+	graphRowHeight  = 24
+	graphHeader     = 48
+)
+
+// svg renders the timeline as SVG. Each bar has a tooltip of the rule name and the times.
+func (g *graph) svg() string {
+	var (
+		buf    strings.Builder
+		chartW = float64(graphWidth - graphLabelWidth - 16)
+		height = graphHeader + graphRowHeight*len(g.rows) + 8
+		xOf    = func(t time.Time) float64 {
+			return graphLabelWidth + float64(t.Sub(g.from))/float64(g.span())*chartW
+		}
+	)
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		graphWidth, height, graphWidth, height)
+	fmt.Fprintf(&buf, `<text x="8" y="18" font-size="14" font-weight="bold">%s (%s - %s UTC)</text>`+"\n",
+		html.EscapeString(g.title), g.from.Format("2006-01-02 15:04"), g.to.Format("2006-01-02 15:04"))
+
+	// ticks every hour for a day, every 6 hours for a few days and every day for longer ones
+	tick, format := time.Hour, "15:04"
+	switch {
+	case g.span() > 72*time.Hour:
+		tick, format = 24*time.Hour, "01-02"
+	case g.span() > 24*time.Hour:
+		tick, format = 6*time.Hour, "01-02 15:04"
+	}
+	for t := g.from.Truncate(tick); !t.After(g.to); t = t.Add(tick) {
+		if t.Before(g.from) {
+			continue
+		}
+		x := xOf(t)
+		fmt.Fprintf(&buf, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="#ddd"/>`+"\n", x, graphHeader-4, x, height-8)
+		fmt.Fprintf(&buf, `<text x="%.1f" y="%d" text-anchor="middle" fill="#666">%s</text>`+"\n", x, graphHeader-8, t.Format(format))
+	}
+
+	for i, row := range g.rows {
+		y := graphHeader + graphRowHeight*i
+		label, color := row.name, "#4a90d9"
+		if row.disabled {
+			label, color = row.name+" (disabled)", "#bbb"
+		}
+		fmt.Fprintf(&buf, `<text x="8" y="%d">%s</text>`+"\n", y+graphRowHeight/2+4, html.EscapeString(label))
+		for _, b := range row.bars {
+			x := xOf(b.start)
+			w := xOf(b.end) - x
+			if w < 1 {
+				w = 1
+			}
+			fmt.Fprintf(&buf, `<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"><title>%s %s - %s</title></rect>`+"\n",
+				x, y+4, w, graphRowHeight-8, color, html.EscapeString(row.name), b.start.Format("2006-01-02 15:04"), b.end.Format("15:04"))
+		}
+	}
+	fmt.Fprintln(&buf, "</svg>")
+	return buf.String()
+}
+
+// html renders the SVG in a standalone page
+func (g *graph) html() string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+</head>
+<body>
+%s</body>
+</html>
+`, html.EscapeString(g.title), g.svg())
+}
@@ -0,0 +1,76 @@
+package ecschedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func testGraph(t *testing.T) *graph {
+	t.Helper()
+	rules := []*Rule{
+		{Name: "morning", ScheduleExpression: "cron(0 9 * * ? *)", ExpectedDuration: "90m"},
+		{Name: "late", ScheduleExpression: "cron(30 23 * * ? *)", ExpectedDuration: "1h", Disabled: true},
+		{Name: "six-hourly", ScheduleExpression: "rate(6 hours)"},
+		{Name: "on-event", EventPattern: map[string]interface{}{"source": []string{"aws.ecs"}}},
+	}
+	from := mustTime(t, "2026-01-01T00:00:00Z")
+	g, err := newGraph("ecschedule: api", rules, from, from.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestGraph_mermaid(t *testing.T) {
+	got := testGraph(t).mermaid()
+	want := `gantt
+    title ecschedule: api (2026-01-01 00:00 - 2026-01-02 00:00 UTC)
+    dateFormat YYYY-MM-DD HH:mm
+    axisFormat %H:%M
+    todayMarker off
+    section morning
+    morning :2026-01-01 09:00, 90m
+    section late
+    late :done, 2026-01-01 23:30, 30m
+    section six-hourly
+    six-hourly :2026-01-01 00:00, 1m
+    six-hourly :2026-01-01 06:00, 1m
+    six-hourly :2026-01-01 12:00, 1m
+    six-hourly :2026-01-01 18:00, 1m
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGraph_svg(t *testing.T) {
+	got := testGraph(t).html()
+	if n := strings.Count(got, "<rect "); n != 6 {
+		t.Errorf("6 bars expected, got %d", n)
+	}
+	for _, s := range []string{
+		"<title>ecschedule: api</title>",
+		">late (disabled)</text>",
+		// 09:00 is at 3/8 of the chart
+		`<rect x="594.0" y="52" width="59.0" height="16" fill="#4a90d9"><title>morning 2026-01-01 09:00 - 10:30</title></rect>`,
+	} {
+		if !strings.Contains(got, s) {
+			t.Errorf("%q is not in the output:\n%s", s, got)
+		}
+	}
+}
+
+func TestParseSpan(t *testing.T) {
+	for s, want := range map[string]time.Duration{"24h": 24 * time.Hour, "7d": 7 * 24 * time.Hour, "90m": 90 * time.Minute} {
+		got, err := parseSpan(s)
+		if err != nil || got != want {
+			t.Errorf("%s: got %s, %v", s, got, err)
+		}
+	}
+	for _, s := range []string{"", "0d", "-1h", "week"} {
+		if _, err := parseSpan(s); err == nil {
+			t.Errorf("%q: error expected", s)
+		}
+	}
+}
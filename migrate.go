@@ -0,0 +1,185 @@
+package ecschedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchevents"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+)
+
+// schedulerRule returns a copy of the rule which is managed with the scheduler backend
+// in the schedule group. When role is specified, it replaces the role of the target,
+// since the role of a schedule must trust scheduler.amazonaws.com.
+func (r *Rule) schedulerRule(group, role string) *Rule {
+	base := *r.BaseConfig
+	base.Backend = backendScheduler
+	base.ScheduleGroup = group
+	base.EventBusName = ""
+	if group == defaultScheduleGroup {
+		base.ScheduleGroup = ""
+	}
+	sr := *r
+	sr.BaseConfig = &base
+	if r.localScheduleExpression != "" {
+		// the scheduler backend handles the timezone including daylight saving time
+		sr.ScheduleExpression = r.localScheduleExpression
+		sr.ScheduleExpressionTimezone = r.ScheduleTimezone
+		sr.localScheduleExpression = ""
+	}
+	var targets []*Target
+	for _, ta := range r.targets() {
+		t := *ta
+		if role != "" {
+			t.Role = role
+		}
+		targets = append(targets, &t)
+	}
+	sr.setTargets(targets)
+	return &sr
+}
+
+// validateMigration checks that the schedule sr can replace the rule r
+func (r *Rule) validateMigration(sr *Rule) error {
+	if r.ScheduleExpression == "" {
+		return errors.New("only rules with scheduleExpression can be migrated")
+	}
+	return sr.validateBackend()
+}
+
+// migrationDiff returns the old rule and the new schedule in YAML. The backend and the
+// schedule group are prepended, so that the diff is not empty even when the parameters
+// are carried over as they are.
+func (r *Rule) migrationDiff(sr *Rule) (from, to string, err error) {
+	from, err = r.localYAMLForDiff()
+	if err != nil {
+		return "", "", err
+	}
+	to, err = sr.localYAMLForDiff()
+	if err != nil {
+		return "", "", err
+	}
+	from = fmt.Sprintf("backend: %s\n", backendEvents) + from
+	to = fmt.Sprintf("backend: %s\nscheduleGroup: %s\n", backendScheduler, sr.scheduleGroup()) + to
+	return from, to, nil
+}
+
+// migrateToScheduler replaces the rule r with the schedule sr. The schedule is created
+// in the DISABLED state and compared with the expected one before the rule is retired,
+// so that the task never runs twice and the rule is left untouched when something goes
+// wrong. The schedule is enabled after the rule is disabled or deleted.
+func (r *Rule) migrateToScheduler(ctx context.Context, awsConf aws.Config, sr *Rule, deleteRule, dryRun bool, format diffFormat) error {
+	from, to, err := r.migrationDiff(sr)
+	if err != nil {
+		return err
+	}
+	var dryRunSuffix string
+	if dryRun {
+		dryRunSuffix = " (dry-run)"
+	}
+	diffOutput := formatDiff(r.Name, from, to, format, explainOptions{})
+	log.Printf("🚚 migrating the rule %q to the schedule group %q%s\n%s", r.Name, sr.scheduleGroup(), dryRunSuffix, diffOutput)
+
+	svc := scheduler.NewFromConfig(awsConf, func(o *scheduler.Options) {
+		o.Region = sr.Region
+	})
+	s, err := sr.remoteSchedule(ctx, svc)
+	if err != nil {
+		return err
+	}
+
+	expected := *sr
+	expected.Disabled = true
+	if s != nil {
+		// resume the migration which was interrupted after the schedule had been created
+		current, err := sr.remoteScheduleYAML(s)
+		if err != nil {
+			return err
+		}
+		want, err := sr.localYAMLForDiff()
+		if err != nil {
+			return err
+		}
+		wantDisabled, err := expected.localYAMLForDiff()
+		if err != nil {
+			return err
+		}
+		switch current {
+		case want:
+			expected.Disabled = sr.Disabled
+		case wantDisabled:
+		default:
+			return fmt.Errorf("schedule %q already exists in the schedule group %q with different parameters\n%s",
+				sr.Name, sr.scheduleGroup(), formatDiff(sr.Name, current, want, format, explainOptions{}))
+		}
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	if s == nil {
+		if err := sr.ensureScheduleGroup(ctx, svc); err != nil {
+			return err
+		}
+		log.Printf("creating the schedule %q in the DISABLED state", sr.Name)
+		if _, err := svc.CreateSchedule(ctx, expected.CreateScheduleInput()); err != nil {
+			return err
+		}
+		s, err = sr.remoteSchedule(ctx, svc)
+		if err != nil {
+			return err
+		}
+	}
+	if err := expected.verifySchedule(s, format); err != nil {
+		return err
+	}
+
+	if deleteRule {
+		if err := r.deleteInternal(ctx, awsConf, false, format); err != nil {
+			return err
+		}
+	} else if !r.Disabled {
+		log.Printf("disabling the rule %q", r.Name)
+		cw := cloudwatchevents.NewFromConfig(awsConf, func(o *cloudwatchevents.Options) {
+			o.Region = r.Region
+		})
+		if _, err := cw.DisableRule(ctx, &cloudwatchevents.DisableRuleInput{
+			Name:         aws.String(r.Name),
+			EventBusName: r.eventBusName(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	if expected.Disabled && !sr.Disabled {
+		log.Printf("enabling the schedule %q", sr.Name)
+		if _, err := svc.UpdateSchedule(ctx, sr.UpdateScheduleInput()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifySchedule checks that the remote schedule s matches the rule
+func (r *Rule) verifySchedule(s *scheduler.GetScheduleOutput, format diffFormat) error {
+	if s == nil {
+		return fmt.Errorf("schedule %q not found in the schedule group %q", r.Name, r.scheduleGroup())
+	}
+	got, err := r.remoteScheduleYAML(s)
+	if err != nil {
+		return err
+	}
+	want, err := r.localYAMLForDiff()
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("schedule %q does not match the rule, left the rule as it is\n%s",
+			r.Name, formatDiff(r.Name, got, want, format, explainOptions{}))
+	}
+	return nil
+}
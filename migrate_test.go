@@ -0,0 +1,127 @@
+package ecschedule
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRule_schedulerRule(t *testing.T) {
+	ru := &Rule{
+		Name:               "hoge-task-name",
+		ScheduleExpression: "cron(0 0 * * ? *)",
+		Target: &Target{
+			TaskDefinition: "task1",
+			Role:           "ecsEventsRole",
+		},
+		BaseConfig: &BaseConfig{
+			Region:     "us-east-1",
+			Cluster:    "api",
+			AccountID:  "339",
+			TrackingID: "api",
+			Backend:    backendEvents,
+		},
+	}
+	sr := ru.schedulerRule("api", "ecsSchedulerRole")
+	if err := ru.validateMigration(sr); err != nil {
+		t.Fatalf("error should be nil, but: %s", err)
+	}
+	if !sr.useScheduler() || sr.scheduleGroup() != "api" {
+		t.Errorf("schedule should be in the schedule group %q, but: %q", "api", sr.scheduleGroup())
+	}
+	if g, e := sr.Target.Role, "ecsSchedulerRole"; g != e {
+		t.Errorf("role should be %q, but: %q", e, g)
+	}
+	// the original rule must be left as it is
+	if ru.useScheduler() || ru.Target.Role != "ecsEventsRole" {
+		t.Errorf("original rule should not be modified: %+v", ru)
+	}
+
+	from, to, err := ru.migrationDiff(sr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(from, "backend: events\n") {
+		t.Errorf("old rule should be prefixed with its backend, but:\n%s", from)
+	}
+	if !strings.HasPrefix(to, "backend: scheduler\nscheduleGroup: api\n") {
+		t.Errorf("new schedule should be prefixed with its backend and group, but:\n%s", to)
+	}
+
+	if g := ru.schedulerRule(defaultScheduleGroup, "").ScheduleGroup; g != "" {
+		t.Errorf("default schedule group should be omitted, but: %q", g)
+	}
+}
+
+func TestRule_validateMigration(t *testing.T) {
+	base := &BaseConfig{Region: "us-east-1", Cluster: "api", AccountID: "339"}
+	testCases := []struct {
+		name   string
+		rule   *Rule
+		expect string
+	}{{
+		name: "no schedule expression",
+		rule: &Rule{
+			Name:       "hoge",
+			Target:     &Target{TaskDefinition: "task1"},
+			BaseConfig: base,
+		},
+		expect: "only rules with scheduleExpression can be migrated",
+	}, {
+		name: "multiple targets",
+		rule: &Rule{
+			Name:               "hoge",
+			ScheduleExpression: "rate(1 hour)",
+			Targets: []*Target{
+				{TaskDefinition: "task1"},
+				{TargetID: "hoge-2", TaskDefinition: "task2"},
+			},
+			BaseConfig: base,
+		},
+		expect: "the scheduler backend supports only a single target",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.validateMigration(tc.rule.schedulerRule(defaultScheduleGroup, ""))
+			if err == nil {
+				t.Fatalf("error should be occurred, but nil")
+			}
+			if g := err.Error(); g != tc.expect {
+				t.Errorf("error should be %q, but: %q", tc.expect, g)
+			}
+		})
+	}
+}
+
+func TestRule_schedulerRule_scheduleTimezone(t *testing.T) {
+	base := &BaseConfig{Region: "ap-northeast-1", Cluster: "api", AccountID: "339", Backend: backendEvents}
+	cr := &Rule{
+		Name:               "hoge-task-name",
+		ScheduleExpression: "cron(0 8 ? * MON-FRI *)",
+		Target:             &Target{TaskDefinition: "task1"},
+		BaseConfig:         &BaseConfig{Region: "ap-northeast-1", Cluster: "api", ScheduleTimezone: "Asia/Tokyo"},
+	}
+	if _, err := cr.convertScheduleTimezone(mustTime(t, "2026-01-01T00:00:00Z")); err != nil {
+		t.Fatal(err)
+	}
+	ru := &Rule{
+		Name:               "hoge-task-name",
+		ScheduleExpression: "cron(0 23 ? * SUN,MON,TUE,WED,THU *)",
+		Target:             &Target{TaskDefinition: "task1"},
+		BaseConfig:         base,
+	}
+	ru.carryScheduleTimezone(cr)
+	sr := ru.schedulerRule(defaultScheduleGroup, "")
+	if sr.ScheduleExpression != "cron(0 8 ? * MON-FRI *)" || sr.ScheduleExpressionTimezone != "Asia/Tokyo" {
+		t.Errorf("schedule should run the local expression in the timezone, but: %s %q", sr.ScheduleExpression, sr.ScheduleExpressionTimezone)
+	}
+	if base.ScheduleTimezone != "" {
+		t.Errorf("base config should not be modified: %+v", base)
+	}
+
+	// the rule on AWS runs another expression
+	other := &Rule{Name: "hoge-task-name", ScheduleExpression: "cron(0 0 * * ? *)", Target: &Target{TaskDefinition: "task1"}, BaseConfig: base}
+	other.carryScheduleTimezone(cr)
+	if sr := other.schedulerRule(defaultScheduleGroup, ""); sr.ScheduleExpression != "cron(0 0 * * ? *)" || sr.ScheduleExpressionTimezone != "" {
+		t.Errorf("schedule should run the remote expression, but: %s %q", sr.ScheduleExpression, sr.ScheduleExpressionTimezone)
+	}
+}
@@ -3,15 +3,19 @@ package ecschedule
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchevents"
 	cweTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchevents/types"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	"github.com/fatih/color"
@@ -26,17 +30,37 @@ import (
 type Rule struct {
 	Name               string `yaml:"name" json:"name"`
 	Description        string `yaml:"description,omitempty" json:"description,omitempty"`
-	ScheduleExpression string `yaml:"scheduleExpression" json:"scheduleExpression"`
+	ScheduleExpression string `yaml:"scheduleExpression,omitempty" json:"scheduleExpression,omitempty"`
 	Disabled           bool   `yaml:"disabled,omitempty" json:"disabled,omitempty"` // ENABLE | DISABLE
-	*Target            `yaml:",inline" json:",inline"`
-	// Targets []*Target `yaml:"targets,omitempty"`
+
+	// EventPattern triggers the rule by events instead of ScheduleExpression
+	EventPattern map[string]interface{} `yaml:"eventPattern,omitempty" json:"eventPattern,omitempty"`
+
+	// following fields are only available with the scheduler backend
+	ScheduleExpressionTimezone string              `yaml:"scheduleExpressionTimezone,omitempty" json:"scheduleExpressionTimezone,omitempty"`
+	FlexibleTimeWindow         *FlexibleTimeWindow `yaml:"flexibleTimeWindow,omitempty" json:"flexibleTimeWindow,omitempty"`
+	StartDate                  *time.Time          `yaml:"startDate,omitempty" json:"startDate,omitempty"`
+	EndDate                    *time.Time          `yaml:"endDate,omitempty" json:"endDate,omitempty"`
+
+	// ExpectedDuration is how long the tasks of the rule are expected to run (e.g. "15m").
+	// It is not sent to AWS and only used by simulate.
+	ExpectedDuration string `yaml:"expectedDuration,omitempty" json:"expectedDuration,omitempty"`
+
+	*Target `yaml:",inline" json:",inline"`
+	Targets []*Target `yaml:"targets,omitempty" json:"targets,omitempty"`
 
 	*BaseConfig `yaml:",inline,omitempty"`
+
+	// localScheduleExpression is the cron expression in scheduleTimezone before it is translated into UTC
+	localScheduleExpression string
+	// scheduleTimezoneWarnings are the warnings of daylight saving time in the translation
+	scheduleTimezoneWarnings []string
 }
 
 // Target cluster
 type Target struct {
 	TargetID                 string                          `yaml:"targetId,omitempty" json:"targetId,omitempty"`
+	TargetCluster            string                          `yaml:"targetCluster,omitempty" json:"targetCluster,omitempty"`
 	TaskDefinition           string                          `yaml:"taskDefinition" json:"taskDefinition"`
 	TaskCount                int32                           `yaml:"taskCount,omitempty" json:"taskCount,omitempty"`
 	TaskOverride             *TaskOverride                   `yaml:"taskOverride,omitempty" json:"taskOverride,omitempty"`
@@ -47,7 +71,14 @@ type Target struct {
 	LaunchType               string                          `yaml:"launch_type,omitempty" json:"launch_type,omitempty"`
 	PlatformVersion          string                          `yaml:"platform_version,omitempty" json:"platform_version,omitempty"`
 	NetworkConfiguration     *NetworkConfiguration           `yaml:"network_configuration,omitempty" json:"network_configuration,omitempty"`
+	PlacementConstraints     []*PlacementConstraint          `yaml:"placementConstraints,omitempty" json:"placementConstraints,omitempty"`
+	PlacementStrategy        []*PlacementStrategy            `yaml:"placementStrategy,omitempty" json:"placementStrategy,omitempty"`
+	EnableECSManagedTags     bool                            `yaml:"enableECSManagedTags,omitempty" json:"enableECSManagedTags,omitempty"`
+	EnableExecuteCommand     bool                            `yaml:"enableExecuteCommand,omitempty" json:"enableExecuteCommand,omitempty"`
+	ReferenceID              string                          `yaml:"referenceId,omitempty" json:"referenceId,omitempty"`
+	Tags                     map[string]string               `yaml:"tags,omitempty" json:"tags,omitempty"` // tags of the task
 	DeadLetterConfig         *DeadLetterConfig               `yaml:"dead_letter_config,omitempty" json:"dead_letter_config,omitempty"`
+	RetryPolicy              *RetryPolicy                    `yaml:"retryPolicy,omitempty" json:"retryPolicy,omitempty"`
 	PropagateTags            *string                         `yaml:"propagateTags,omitempty" json:"propagateTags,omitempty"`
 }
 
@@ -58,22 +89,134 @@ type CapacityProviderStrategyItem struct {
 	Weight           int32  `yaml:"weight" json:"weight"`
 }
 
+// PlacementConstraint represents ECS task placement constraint
+type PlacementConstraint struct {
+	Type       string `yaml:"type" json:"type"` // distinctInstance | memberOf
+	Expression string `yaml:"expression,omitempty" json:"expression,omitempty"`
+}
+
+// PlacementStrategy represents ECS task placement strategy
+type PlacementStrategy struct {
+	Type  string `yaml:"type" json:"type"` // random | spread | binpack
+	Field string `yaml:"field,omitempty" json:"field,omitempty"`
+}
+
+// validatePlacement validates placement constraints and placement strategy of the target
+// in the same way as ECS does.
+func (ta *Target) validatePlacement() error {
+	if len(ta.PlacementConstraints) == 0 && len(ta.PlacementStrategy) == 0 {
+		return nil
+	}
+	if ta.LaunchType == string(ecsTypes.LaunchTypeFargate) {
+		return errors.New("placementConstraints and placementStrategy are not available with the FARGATE launch type")
+	}
+	if len(ta.PlacementConstraints) > 10 {
+		return errors.New("up to 10 placementConstraints are allowed")
+	}
+	for _, pc := range ta.PlacementConstraints {
+		switch ecsTypes.PlacementConstraintType(pc.Type) {
+		case ecsTypes.PlacementConstraintTypeDistinctInstance:
+			if pc.Expression != "" {
+				return errors.New("expression is not available with the distinctInstance placement constraint")
+			}
+		case ecsTypes.PlacementConstraintTypeMemberOf:
+			if pc.Expression == "" {
+				return errors.New("expression is required for the memberOf placement constraint")
+			}
+		default:
+			return fmt.Errorf("invalid placement constraint type %q", pc.Type)
+		}
+	}
+	if len(ta.PlacementStrategy) > 5 {
+		return errors.New("up to 5 placementStrategy are allowed")
+	}
+	for _, ps := range ta.PlacementStrategy {
+		switch ecsTypes.PlacementStrategyType(ps.Type) {
+		case ecsTypes.PlacementStrategyTypeRandom:
+			if ps.Field != "" {
+				return errors.New("field is not available with the random placement strategy")
+			}
+		case ecsTypes.PlacementStrategyTypeSpread:
+			if ps.Field == "" {
+				return errors.New("field is required for the spread placement strategy")
+			}
+		case ecsTypes.PlacementStrategyTypeBinpack:
+			if ps.Field != "cpu" && ps.Field != "memory" {
+				return fmt.Errorf("field of the binpack placement strategy must be cpu or memory, but: %q", ps.Field)
+			}
+		default:
+			return fmt.Errorf("invalid placement strategy type %q", ps.Type)
+		}
+	}
+	return nil
+}
+
 // NOTE: ContainerOverrides should conceptually be inside TaskOverride (cf. https://docs.aws.amazon.com/AmazonECS/latest/APIReference/API_TaskOverride.html).
 // However, for backward-compatibility, we keep ContainerOverrides and TaskOverride as separate fields and merge them into a single struct in `apply` and `run`.
 // That's why containerOverrides field is not inside TaskOverride.
 type TaskOverride struct {
 	Cpu    *string `yaml:"cpu,omitempty" json:"cpu,omitempty"`
 	Memory *string `yaml:"memory,omitempty" json:"memory,omitempty"`
+	// TaskRoleArn and ExecutionRoleArn accept a role name as well as Role of Target does
+	TaskRoleArn                   *string                         `yaml:"taskRoleArn,omitempty" json:"taskRoleArn,omitempty"`
+	ExecutionRoleArn              *string                         `yaml:"executionRoleArn,omitempty" json:"executionRoleArn,omitempty"`
+	EphemeralStorage              *EphemeralStorage               `yaml:"ephemeralStorage,omitempty" json:"ephemeralStorage,omitempty"`
+	InferenceAcceleratorOverrides []*InferenceAcceleratorOverride `yaml:"inferenceAcceleratorOverrides,omitempty" json:"inferenceAcceleratorOverrides,omitempty"`
+}
+
+// withRoleNames returns a copy of the task override with the ARNs of the roles in the same account
+// trimmed to the role names, as the remote task override is read
+func (to *TaskOverride) withRoleNames(roleArnPrefix string) *TaskOverride {
+	trim := func(arn *string) *string {
+		if arn == nil {
+			return nil
+		}
+		return aws.String(strings.TrimPrefix(*arn, roleArnPrefix))
+	}
+	nto := *to
+	nto.TaskRoleArn = trim(to.TaskRoleArn)
+	nto.ExecutionRoleArn = trim(to.ExecutionRoleArn)
+	return &nto
+}
+
+func (to *TaskOverride) isEmpty() bool {
+	return to.Cpu == nil && to.Memory == nil && to.TaskRoleArn == nil && to.ExecutionRoleArn == nil &&
+		to.EphemeralStorage == nil && len(to.InferenceAcceleratorOverrides) == 0
+}
+
+// EphemeralStorage represents the ephemeral storage of the task
+type EphemeralStorage struct {
+	SizeInGiB int32 `yaml:"sizeInGiB" json:"sizeInGiB"`
+}
+
+// InferenceAcceleratorOverride overrides the Elastic Inference accelerator of the task
+type InferenceAcceleratorOverride struct {
+	DeviceName string `yaml:"deviceName,omitempty" json:"deviceName,omitempty"`
+	DeviceType string `yaml:"deviceType,omitempty" json:"deviceType,omitempty"`
 }
 
 // ContainerOverride overrides container
 type ContainerOverride struct {
-	Name              string            `yaml:"name" json:"name"`
-	Command           []string          `yaml:"command,flow" json:"command"` // ,flow
-	Environment       map[string]string `yaml:"environment,omitempty" json:"environment,omitempty"`
-	Cpu               *int32            `yaml:"cpu,omitempty" json:"cpu,omitempty"`
-	Memory            *int32            `yaml:"memory,omitempty" json:"memory,omitempty"`
-	MemoryReservation *int32            `yaml:"memoryReservation,omitempty" json:"memoryReservation,omitempty"`
+	Name                 string                 `yaml:"name" json:"name"`
+	Command              []string               `yaml:"command,flow" json:"command"` // ,flow
+	Environment          map[string]string      `yaml:"environment,omitempty" json:"environment,omitempty"`
+	EnvironmentFiles     []*EnvironmentFile     `yaml:"environmentFiles,omitempty" json:"environmentFiles,omitempty"`
+	Cpu                  *int32                 `yaml:"cpu,omitempty" json:"cpu,omitempty"`
+	Memory               *int32                 `yaml:"memory,omitempty" json:"memory,omitempty"`
+	MemoryReservation    *int32                 `yaml:"memoryReservation,omitempty" json:"memoryReservation,omitempty"`
+	ResourceRequirements []*ResourceRequirement `yaml:"resourceRequirements,omitempty" json:"resourceRequirements,omitempty"`
+}
+
+// EnvironmentFile represents a file containing environment variables of the container
+type EnvironmentFile struct {
+	Type  string `yaml:"type" json:"type"` // s3
+	Value string `yaml:"value" json:"value"`
+}
+
+// ResourceRequirement represents the resource to assign to the container
+type ResourceRequirement struct {
+	Type  string `yaml:"type" json:"type"` // GPU | InferenceAccelerator
+	Value string `yaml:"value" json:"value"`
 }
 
 // A DeadLetterConfig object that contains information about a dead-letter queue
@@ -83,13 +226,51 @@ type DeadLetterConfig struct {
 }
 
 func (dlc *DeadLetterConfig) sqsArn(r *Rule) string {
-	ta := r.Target
+	if strings.HasPrefix(dlc.Sqs, "arn:") {
+		return dlc.Sqs
+	}
+
+	return fmt.Sprintf("arn:aws:sqs:%s:%s:%s", r.Region, r.AccountID, dlc.Sqs)
+}
+
+const (
+	defaultMaximumRetryAttempts     = 185
+	defaultMaximumEventAgeInSeconds = 86400
+)
+
+// RetryPolicy represents how long EventBridge keeps retrying to start the task
+type RetryPolicy struct {
+	MaximumRetryAttempts     *int32 `yaml:"maximumRetryAttempts,omitempty" json:"maximumRetryAttempts,omitempty"`         // 0-185
+	MaximumEventAgeInSeconds *int32 `yaml:"maximumEventAgeInSeconds,omitempty" json:"maximumEventAgeInSeconds,omitempty"` // 60-86400
+}
 
-	if strings.HasPrefix(ta.DeadLetterConfig.Sqs, "arn:") {
-		return ta.DeadLetterConfig.Sqs
+func (rp *RetryPolicy) validate() error {
+	if n := rp.MaximumRetryAttempts; n != nil && (*n < 0 || *n > defaultMaximumRetryAttempts) {
+		return fmt.Errorf("retryPolicy.maximumRetryAttempts must be between 0 and %d", defaultMaximumRetryAttempts)
 	}
+	if n := rp.MaximumEventAgeInSeconds; n != nil && (*n < 60 || *n > defaultMaximumEventAgeInSeconds) {
+		return fmt.Errorf("retryPolicy.maximumEventAgeInSeconds must be between 60 and %d", defaultMaximumEventAgeInSeconds)
+	}
+	return nil
+}
 
-	return fmt.Sprintf("arn:aws:sqs:%s:%s:%s", r.Region, r.AccountID, r.DeadLetterConfig.Sqs)
+// normalize drops the fields which are equal to the defaults, since the remote target may
+// return them even when they are not specified. It returns nil when all fields are dropped.
+func (rp *RetryPolicy) normalize() *RetryPolicy {
+	if rp == nil {
+		return nil
+	}
+	n := &RetryPolicy{}
+	if v := rp.MaximumRetryAttempts; v != nil && *v != defaultMaximumRetryAttempts {
+		n.MaximumRetryAttempts = v
+	}
+	if v := rp.MaximumEventAgeInSeconds; v != nil && *v != defaultMaximumEventAgeInSeconds {
+		n.MaximumEventAgeInSeconds = v
+	}
+	if n.MaximumRetryAttempts == nil && n.MaximumEventAgeInSeconds == nil {
+		return nil
+	}
+	return n
 }
 
 // NetworkConfiguration represents ECS network configuration
@@ -119,23 +300,8 @@ func (nc *NetworkConfiguration) ecsParameters() *cweTypes.NetworkConfiguration {
 	}
 }
 
-func (nc *NetworkConfiguration) inputParameters() *ecsTypes.NetworkConfiguration {
-	awsVpcConfiguration := &ecsTypes.AwsVpcConfiguration{
-		Subnets: nc.AwsVpcConfiguration.Subnets,
-	}
-	if as := nc.AwsVpcConfiguration.AssignPublicIP; as != "" {
-		awsVpcConfiguration.AssignPublicIp = ecsTypes.AssignPublicIp(as)
-	}
-	if sgs := nc.AwsVpcConfiguration.SecurityGroups; len(sgs) > 0 {
-		awsVpcConfiguration.SecurityGroups = sgs
-	}
-	return &ecsTypes.NetworkConfiguration{
-		AwsvpcConfiguration: awsVpcConfiguration,
-	}
-}
-
 func (ta *Target) targetID(r *Rule) string {
-	if r.TargetID == "" {
+	if ta.TargetID == "" {
 		return r.Name
 	}
 	return ta.TargetID
@@ -148,13 +314,15 @@ func (ta *Target) taskCount() int32 {
 	return ta.TaskCount
 }
 
-// NewRuleFromRemote creates a new rule from remote (AWS EventBridge Rule)
+// NewRuleFromRemote creates a new rule from remote (AWS EventBridge Rule). It returns nil
+// when the rule does not run an ECS task on the cluster.
 func NewRuleFromRemote(ctx context.Context, awsConf aws.Config, bc *BaseConfig, ruleName string) (*Rule, error) {
 	cw := cloudwatchevents.NewFromConfig(awsConf, func(o *cloudwatchevents.Options) {
 		o.Region = bc.Region
 	})
 	remoteRule, err := cw.DescribeRule(ctx, &cloudwatchevents.DescribeRuleInput{
-		Name: aws.String(ruleName),
+		Name:         aws.String(ruleName),
+		EventBusName: bc.eventBusName(),
 	})
 	if err != nil {
 		return nil, err
@@ -163,10 +331,11 @@ func NewRuleFromRemote(ctx context.Context, awsConf aws.Config, bc *BaseConfig,
 	var (
 		rg = &ruleGetter{
 			svc:              cw,
-			ruleArnPrefix:    fmt.Sprintf("arn:aws:events:%s:%s:rule/", bc.Region, bc.AccountID),
+			ruleArnPrefix:    ruleArnPrefix(bc.Region, bc.AccountID, bc.EventBusName),
 			clusterArn:       fmt.Sprintf("arn:aws:ecs:%s:%s:cluster/%s", bc.Region, bc.AccountID, bc.Cluster),
 			taskDefArnPrefix: fmt.Sprintf("arn:aws:ecs:%s:%s:task-definition/", bc.Region, bc.AccountID),
 			roleArnPrefix:    fmt.Sprintf("arn:aws:iam::%s:role/", bc.AccountID),
+			sqsArnPrefix:     fmt.Sprintf("arn:aws:sqs:%s:%s:", bc.Region, bc.AccountID),
 		}
 	)
 
@@ -182,7 +351,7 @@ func NewRuleFromRemote(ctx context.Context, awsConf aws.Config, bc *BaseConfig,
 		State:              remoteRule.State,
 	}
 	ru, err := rg.getRule(ctx, cwRule)
-	if err != nil {
+	if err != nil || ru == nil {
 		return nil, err
 	}
 
@@ -190,40 +359,110 @@ func NewRuleFromRemote(ctx context.Context, awsConf aws.Config, bc *BaseConfig,
 		Name:               ru.Name,
 		Description:        ru.Description,
 		ScheduleExpression: ru.ScheduleExpression,
+		EventPattern:       ru.EventPattern,
 		Disabled:           ru.Disabled,
 		Target:             ru.Target,
+		Targets:            ru.Targets,
 		BaseConfig:         bc,
 	}
 	return newRule, nil
 }
 
+// targets returns all targets of the rule: the inline one (if any) followed by the `targets` list
+func (r *Rule) targets() []*Target {
+	var targets []*Target
+	if r.Target != nil && r.Target.TaskDefinition != "" {
+		targets = append(targets, r.Target)
+	}
+	return append(targets, r.Targets...)
+}
+
+// setTargets stores targets in their canonical form: a single target is held inline and
+// multiple targets are held in the `targets` list, sorted by target ID.
+func (r *Rule) setTargets(targets []*Target) {
+	sort.SliceStable(targets, func(i, j int) bool {
+		return targets[i].targetID(r) < targets[j].targetID(r)
+	})
+	r.Target, r.Targets = nil, nil
+	switch len(targets) {
+	case 0:
+	case 1:
+		r.Target = targets[0]
+	default:
+		r.Targets = targets
+	}
+}
+
 func (r *Rule) roleARN() string {
-	if strings.HasPrefix(r.Role, "arn:") {
-		return r.Role
+	targets := r.targets()
+	if len(targets) == 0 {
+		return (&Target{}).roleARN(r)
 	}
-	role := r.Role
+	return targets[0].roleARN(r)
+}
+
+func (ta *Target) roleARN(r *Rule) string {
+	if strings.HasPrefix(ta.Role, "arn:") {
+		return ta.Role
+	}
+	role := ta.Role
 	if role == "" {
 		role = defaultRole
 	}
 	return fmt.Sprintf("arn:aws:iam::%s:role/%s", r.AccountID, role)
 }
 
+// roleARN expands the role name into the ARN in the account of the rule
+func roleARN(r *Rule, role *string) *string {
+	if role == nil || strings.HasPrefix(*role, "arn:") {
+		return role
+	}
+	return aws.String(fmt.Sprintf("arn:aws:iam::%s:role/%s", r.AccountID, *role))
+}
+
 func (r *Rule) ruleARN() string {
-	return fmt.Sprintf("arn:aws:events:%s:%s:rule/%s", r.Region, r.AccountID, r.Name)
+	return ruleArnPrefix(r.Region, r.AccountID, r.EventBusName) + r.Name
+}
+
+// ruleArnPrefix returns the prefix of ARNs of the rules on the event bus. The name of the
+// event bus is included in the ARN unless it is the default one.
+func ruleArnPrefix(region, accountID, eventBusName string) string {
+	if eventBusName == "" || eventBusName == defaultEventBusName {
+		return fmt.Sprintf("arn:aws:events:%s:%s:rule/", region, accountID)
+	}
+	return fmt.Sprintf("arn:aws:events:%s:%s:rule/%s/", region, accountID, eventBusName)
+}
+
+// eventBusName returns the event bus name for API calls. It returns nil for the default
+// event bus.
+func (bc *BaseConfig) eventBusName() *string {
+	if bc.EventBusName == "" {
+		return nil
+	}
+	return aws.String(bc.EventBusName)
+}
+
+// cluster returns the cluster the target runs on. It defaults to the cluster of the rule.
+func (ta *Target) cluster(r *Rule) string {
+	if ta.TargetCluster != "" {
+		return ta.TargetCluster
+	}
+	return r.Cluster
 }
 
 func (ta *Target) targetARN(r *Rule) string {
-	if strings.HasPrefix(r.Cluster, "arn:") {
-		return r.Cluster
+	cluster := ta.cluster(r)
+	if strings.HasPrefix(cluster, "arn:") {
+		return cluster
 	}
-	return fmt.Sprintf("arn:aws:ecs:%s:%s:cluster/%s", r.Region, r.AccountID, r.Cluster)
+	return fmt.Sprintf("arn:aws:ecs:%s:%s:cluster/%s", r.Region, r.AccountID, cluster)
 }
 
 func (ta *Target) taskDefinitionArn(r *Rule) string {
-	if strings.HasPrefix(r.TaskDefinition, "arn:") {
-		return r.TaskDefinition
+	if strings.HasPrefix(ta.TaskDefinition, "arn:") {
+		return ta.TaskDefinition
 	}
-	return fmt.Sprintf("arn:aws:ecs:%s:%s:task-definition/%s", r.Region, r.AccountID, r.TaskDefinition)
+	return fmt.Sprintf("arn:aws:ecs:%s:%s:task-definition/%s", r.Region, r.AccountID, ta.TaskDefinition)
 }
 
 func (r *Rule) state() string {
@@ -233,12 +472,11 @@ func (r *Rule) state() string {
 	return "ENABLED"
 }
 
-func (r *Rule) ecsParameters() *cweTypes.EcsParameters {
+func (ta *Target) ecsParameters(r *Rule) *cweTypes.EcsParameters {
 	p := cweTypes.EcsParameters{
-		TaskDefinitionArn: aws.String(r.taskDefinitionArn(r)),
-		TaskCount:         aws.Int32(r.taskCount()),
+		TaskDefinitionArn: aws.String(ta.taskDefinitionArn(r)),
+		TaskCount:         aws.Int32(ta.taskCount()),
 	}
-	ta := r.Target
 	if ta.Group != "" {
 		p.Group = aws.String(ta.Group)
 	}
@@ -265,12 +503,45 @@ func (r *Rule) ecsParameters() *cweTypes.EcsParameters {
 	if nc := ta.NetworkConfiguration; nc != nil {
 		p.NetworkConfiguration = nc.ecsParameters()
 	}
+	for _, pc := range ta.PlacementConstraints {
+		c := cweTypes.PlacementConstraint{Type: cweTypes.PlacementConstraintType(pc.Type)}
+		if pc.Expression != "" {
+			c.Expression = aws.String(pc.Expression)
+		}
+		p.PlacementConstraints = append(p.PlacementConstraints, c)
+	}
+	for _, ps := range ta.PlacementStrategy {
+		st := cweTypes.PlacementStrategy{Type: cweTypes.PlacementStrategyType(ps.Type)}
+		if ps.Field != "" {
+			st.Field = aws.String(ps.Field)
+		}
+		p.PlacementStrategy = append(p.PlacementStrategy, st)
+	}
+	p.EnableECSManagedTags = ta.EnableECSManagedTags
+	p.EnableExecuteCommand = ta.EnableExecuteCommand
+	if ta.ReferenceID != "" {
+		p.ReferenceId = aws.String(ta.ReferenceID)
+	}
+	for _, k := range ta.tagKeys() {
+		p.Tags = append(p.Tags, cweTypes.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(ta.Tags[k]),
+		})
+	}
 	return &p
 }
 
-func (r *Rule) deadLetterConfigParameters() *cweTypes.DeadLetterConfig {
-	ta := r.Target
+// tagKeys returns the keys of the task tags in order to keep API parameters stable
+func (ta *Target) tagKeys() []string {
+	keys := make([]string, 0, len(ta.Tags))
+	for k := range ta.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
 
+func (ta *Target) deadLetterConfigParameters(r *Rule) *cweTypes.DeadLetterConfig {
 	if dlc := ta.DeadLetterConfig; dlc != nil {
 		arn := dlc.sqsArn(r)
 		return &cweTypes.DeadLetterConfig{
@@ -281,10 +552,21 @@ func (r *Rule) deadLetterConfigParameters() *cweTypes.DeadLetterConfig {
 	return nil
 }
 
+func (ta *Target) retryPolicyParameters() *cweTypes.RetryPolicy {
+	if rp := ta.RetryPolicy; rp != nil {
+		return &cweTypes.RetryPolicy{
+			MaximumRetryAttempts:     rp.MaximumRetryAttempts,
+			MaximumEventAgeInSeconds: rp.MaximumEventAgeInSeconds,
+		}
+	}
+	return nil
+}
+
 func (r *Rule) mergeBaseConfig(bc *BaseConfig, role string) {
-	if r.Role == "" {
-		// XXX care multiple target
-		r.Role = role
+	for _, ta := range r.targets() {
+		if ta.Role == "" {
+			ta.Role = role
+		}
 	}
 	if r.BaseConfig == nil {
 		r.BaseConfig = bc
@@ -302,24 +584,56 @@ func (r *Rule) mergeBaseConfig(bc *BaseConfig, role string) {
 	if r.TrackingID == "" {
 		r.TrackingID = bc.TrackingID
 	}
+	if r.Backend == "" {
+		r.Backend = bc.Backend
+	}
+	if r.ScheduleGroup == "" {
+		r.ScheduleGroup = bc.ScheduleGroup
+	}
+	if r.EventBusName == "" {
+		r.EventBusName = bc.EventBusName
+	}
+	if r.ScheduleTimezone == "" {
+		r.ScheduleTimezone = bc.ScheduleTimezone
+	}
+}
+
+// hideEnvironment drops environment variables of container overrides so that they are not logged
+func (r *Rule) hideEnvironment() {
+	for _, ta := range r.targets() {
+		for _, v := range ta.ContainerOverrides {
+			v.Environment = nil
+		}
+	}
 }
 
 // PutRuleInput puts rule input
 func (r *Rule) PutRuleInput() *cloudwatchevents.PutRuleInput {
-	return &cloudwatchevents.PutRuleInput{
-		Description:        aws.String(r.Description),
-		Name:               aws.String(r.Name),
-		RoleArn:            aws.String(r.roleARN()),
-		ScheduleExpression: aws.String(r.ScheduleExpression),
-		State:              cweTypes.RuleState(r.state()),
-	}
+	in := &cloudwatchevents.PutRuleInput{
+		Description:  aws.String(r.Description),
+		Name:         aws.String(r.Name),
+		EventBusName: r.eventBusName(),
+		RoleArn:      aws.String(r.roleARN()),
+		State:        cweTypes.RuleState(r.state()),
+	}
+	if r.ScheduleExpression != "" {
+		in.ScheduleExpression = aws.String(r.ScheduleExpression)
+	}
+	// the event pattern has already been validated in LoadConfig
+	in.EventPattern, _ = r.eventPatternJSON()
+	return in
 }
 
 // PutTargetsInput puts targets input
 func (r *Rule) PutTargetsInput() *cloudwatchevents.PutTargetsInput {
+	var targets []cweTypes.Target
+	for _, ta := range r.targets() {
+		targets = append(targets, *ta.target(r))
+	}
 	return &cloudwatchevents.PutTargetsInput{
-		Rule:    aws.String(r.Name),
-		Targets: []cweTypes.Target{*r.target()},
+		Rule:         aws.String(r.Name),
+		EventBusName: r.eventBusName(),
+		Targets:      targets,
 	}
 }
 
@@ -329,7 +643,7 @@ func (r *Rule) TagResourceInput() *cloudwatchevents.TagResourceInput {
 		ResourceARN: aws.String(r.ruleARN()),
 		Tags: []cweTypes.Tag{
 			{
-				Key:   aws.String("ecschedule:tracking-id"),
+				Key:   aws.String(trackingIDTagKey),
 				Value: aws.String(r.TrackingID),
 			},
 		},
@@ -337,18 +651,24 @@ func (r *Rule) TagResourceInput() *cloudwatchevents.TagResourceInput {
 }
 
 type taskOverrideJSON struct {
-	Cpu                *string                  `json:"cpu,omitempty"`
-	Memory             *string                  `json:"memory,omitempty"`
-	ContainerOverrides []*containerOverrideJSON `json:"containerOverrides"`
+	Cpu                           *string                         `json:"cpu,omitempty"`
+	Memory                        *string                         `json:"memory,omitempty"`
+	TaskRoleArn                   *string                         `json:"taskRoleArn,omitempty"`
+	ExecutionRoleArn              *string                         `json:"executionRoleArn,omitempty"`
+	EphemeralStorage              *EphemeralStorage               `json:"ephemeralStorage,omitempty"`
+	InferenceAcceleratorOverrides []*InferenceAcceleratorOverride `json:"inferenceAcceleratorOverrides,omitempty"`
+	ContainerOverrides            []*containerOverrideJSON        `json:"containerOverrides"`
 }
 
 type containerOverrideJSON struct {
-	Name              string    `json:"name"`
-	Command           []string  `json:"command,omitempty"`
-	Environment       []*kvPair `json:"environment,omitempty"`
-	Cpu               *int32    `json:"cpu,omitempty"`
-	Memory            *int32    `json:"memory,omitempty"`
-	MemoryReservation *int32    `json:"memoryReservation,omitempty"`
+	Name                 string                 `json:"name"`
+	Command              []string               `json:"command,omitempty"`
+	Environment          []*kvPair              `json:"environment,omitempty"`
+	EnvironmentFiles     []*EnvironmentFile     `json:"environmentFiles,omitempty"`
+	Cpu                  *int32                 `json:"cpu,omitempty"`
+	Memory               *int32                 `json:"memory,omitempty"`
+	MemoryReservation    *int32                 `json:"memoryReservation,omitempty"`
+	ResourceRequirements []*ResourceRequirement `json:"resourceRequirements,omitempty"`
 }
 
 type kvPair struct {
@@ -356,16 +676,17 @@ type kvPair struct {
 	Value string `json:"value"`
 }
 
-func (r *Rule) target() *cweTypes.Target {
-	if r.Target == nil {
-		return nil
-	}
+func (ta *Target) target(r *Rule) *cweTypes.Target {
 	toj := &taskOverrideJSON{}
-	if to := r.TaskOverride; to != nil {
+	if to := ta.TaskOverride; to != nil {
 		toj.Cpu = to.Cpu
 		toj.Memory = to.Memory
+		toj.TaskRoleArn = roleARN(r, to.TaskRoleArn)
+		toj.ExecutionRoleArn = roleARN(r, to.ExecutionRoleArn)
+		toj.EphemeralStorage = to.EphemeralStorage
+		toj.InferenceAcceleratorOverrides = to.InferenceAcceleratorOverrides
 	}
-	for _, co := range r.ContainerOverrides {
+	for _, co := range ta.ContainerOverrides {
 		var kvPairs []*kvPair
 		for k, v := range co.Environment {
 			kvPairs = append(kvPairs, &kvPair{
@@ -374,22 +695,25 @@ func (r *Rule) target() *cweTypes.Target {
 			})
 		}
 		toj.ContainerOverrides = append(toj.ContainerOverrides, &containerOverrideJSON{
-			Name:              co.Name,
-			Command:           co.Command,
-			Environment:       kvPairs,
-			Cpu:               co.Cpu,
-			Memory:            co.Memory,
-			MemoryReservation: co.MemoryReservation,
+			Name:                 co.Name,
+			Command:              co.Command,
+			Environment:          kvPairs,
+			EnvironmentFiles:     co.EnvironmentFiles,
+			Cpu:                  co.Cpu,
+			Memory:               co.Memory,
+			MemoryReservation:    co.MemoryReservation,
+			ResourceRequirements: co.ResourceRequirements,
 		})
 	}
 	bs, _ := json.Marshal(toj)
 
 	return &cweTypes.Target{
-		Id:               aws.String(r.targetID(r)),
-		Arn:              aws.String(r.targetARN(r)),
-		RoleArn:          aws.String(r.roleARN()),
-		EcsParameters:    r.ecsParameters(),
-		DeadLetterConfig: r.deadLetterConfigParameters(),
+		Id:               aws.String(ta.targetID(r)),
+		Arn:              aws.String(ta.targetARN(r)),
+		RoleArn:          aws.String(ta.roleARN(r)),
+		EcsParameters:    ta.ecsParameters(r),
+		DeadLetterConfig: ta.deadLetterConfigParameters(r),
+		RetryPolicy:      ta.retryPolicyParameters(),
 		Input:            aws.String(string(bs)),
 	}
 }
@@ -459,11 +783,13 @@ func (r *Rule) validateTaskDefinition(ctx context.Context, awsConf aws.Config) e
 	svc := ecs.NewFromConfig(awsConf, func(o *ecs.Options) {
 		o.Region = r.Region
 	})
-	input := &ecs.DescribeTaskDefinitionInput{
-		TaskDefinition: aws.String(r.Target.TaskDefinition),
-	}
-	if _, err := svc.DescribeTaskDefinition(ctx, input); err != nil {
-		return fmt.Errorf("task definition %s is not defined: %s", r.Target.TaskDefinition, err.Error())
+	for _, ta := range r.targets() {
+		input := &ecs.DescribeTaskDefinitionInput{
+			TaskDefinition: aws.String(ta.TaskDefinition),
+		}
+		if _, err := svc.DescribeTaskDefinition(ctx, input); err != nil {
+			return fmt.Errorf("task definition %s is not defined: %s", ta.TaskDefinition, err.Error())
+		}
 	}
 	return nil
 }
@@ -487,11 +813,14 @@ func (r *Rule) applyInternal(ctx context.Context, awsConf aws.Config, dryRun boo
 	if err := r.validateTaskDefinition(ctx, awsConf); err != nil {
 		return err
 	}
+	if r.useScheduler() {
+		return r.applySchedule(ctx, awsConf, dryRun, format)
+	}
 	svc := cloudwatchevents.NewFromConfig(awsConf, func(o *cloudwatchevents.Options) {
 		o.Region = r.Region
 	})
 
-	from, to, err := r.diff(ctx, svc)
+	from, to, staleIDs, err := r.diffTargets(ctx, svc)
 	if err != nil {
 		return err
 	}
@@ -505,7 +834,7 @@ func (r *Rule) applyInternal(ctx context.Context, awsConf aws.Config, dryRun boo
 		dryRunSuffix = " (dry-run)"
 	}
 
-	diffOutput := formatDiff(r.Name, from, to, format)
+	diffOutput := formatDiff(r.Name, from, to, format, explainOptions{})
 	log.Printf("💡 applying following changes%s\n%s", dryRunSuffix, diffOutput)
 
 	if dryRun {
@@ -514,6 +843,10 @@ func (r *Rule) applyInternal(ctx context.Context, awsConf aws.Config, dryRun boo
 	if _, err := svc.PutRule(ctx, r.PutRuleInput()); err != nil {
 		return err
 	}
+	// Remove stale targets first not to exceed the limit of targets per rule.
+	if err := r.removeTargets(ctx, svc, staleIDs); err != nil {
+		return err
+	}
 	if _, err = svc.PutTargets(ctx, r.PutTargetsInput()); err != nil {
 		return err
 	}
@@ -522,83 +855,119 @@ func (r *Rule) applyInternal(ctx context.Context, awsConf aws.Config, dryRun boo
 	return err
 }
 
-// Run the rule
-func (r *Rule) Run(ctx context.Context, awsConf aws.Config, noWait bool) error {
-	if err := r.validateEnv(); err != nil {
-		return err
-	}
-	if err := r.validateTFstate(); err != nil {
-		return err
-	}
-	if err := r.validateSSM(); err != nil {
-		return err
-	}
-	svc := ecs.NewFromConfig(awsConf, func(o *ecs.Options) {
-		o.Region = r.Region
-	})
-	var containerOverrides []ecsTypes.ContainerOverride
-	for _, co := range r.ContainerOverrides {
-		var (
-			kvPairs []ecsTypes.KeyValuePair
-			command []string
-		)
-		for k, v := range co.Environment {
-			kvPairs = append(kvPairs, ecsTypes.KeyValuePair{
-				Name:  aws.String(k),
-				Value: aws.String(v),
-			})
+// remoteTargets returns all targets attached to the remote rule
+func (r *Rule) remoteTargets(ctx context.Context, svc *cloudwatchevents.Client) ([]cweTypes.Target, error) {
+	var (
+		targets   []cweTypes.Target
+		nextToken *string
+	)
+	for {
+		out, err := svc.ListTargetsByRule(ctx, &cloudwatchevents.ListTargetsByRuleInput{
+			Rule:         aws.String(r.Name),
+			EventBusName: r.eventBusName(),
+			NextToken:    nextToken,
+		})
+		if err != nil {
+			var nfe *cweTypes.ResourceNotFoundException
+			if errors.As(err, &nfe) {
+				// the rule has not been created yet
+				return nil, nil
+			}
+			return nil, err
 		}
-		for _, v := range co.Command {
-			command = append(command, v)
+		targets = append(targets, out.Targets...)
+		if out.NextToken == nil {
+			break
 		}
-		containerOverrides = append(containerOverrides, ecsTypes.ContainerOverride{
-			Name:              aws.String(co.Name),
-			Environment:       kvPairs,
-			Command:           command,
-			Cpu:               co.Cpu,
-			Memory:            co.Memory,
-			MemoryReservation: co.MemoryReservation,
-		})
+		nextToken = out.NextToken
 	}
+	return targets, nil
+}
 
-	var taskOverride ecsTypes.TaskOverride
-	if to := r.TaskOverride; to != nil {
-		taskOverride.Cpu = to.Cpu
-		taskOverride.Memory = to.Memory
+// staleTargetIDs returns IDs of the remote ECS targets on the cluster which are no longer declared
+// in the rule. Targets of other kinds or on other clusters are left as they are, since they may
+// not be created by ecschedule.
+func (r *Rule) staleTargetIDs(remote []cweTypes.Target) []string {
+	declared := map[string]bool{}
+	for _, ta := range r.targets() {
+		declared[ta.targetID(r)] = true
+	}
+	clusterArn := (&Target{}).targetARN(r)
+	var stale []string
+	for _, t := range remote {
+		if t.EcsParameters == nil || aws.ToString(t.Arn) != clusterArn {
+			continue
+		}
+		if id := aws.ToString(t.Id); !declared[id] {
+			stale = append(stale, id)
+		}
 	}
-	taskOverride.ContainerOverrides = containerOverrides
+	return stale
+}
 
-	var networkConfiguration *ecsTypes.NetworkConfiguration
-	if r.NetworkConfiguration != nil {
-		networkConfiguration = r.NetworkConfiguration.inputParameters()
-	}
-	var propagateTags ecsTypes.PropagateTags
-	if r.PropagateTags != nil {
-		propagateTags = ecsTypes.PropagateTags(*r.PropagateTags)
+// annotateStaleTargets notes the stale targets removed by apply at the end of the rule YAML, so
+// that the diff shows them
+func annotateStaleTargets(ruleYAML string, staleIDs []string) string {
+	if len(staleIDs) == 0 {
+		return ruleYAML
 	}
+	return ruleYAML + fmt.Sprintf("# stale targets to be removed: %s\n", strings.Join(staleIDs, ", "))
+}
 
-	out, err := svc.RunTask(ctx,
-		&ecs.RunTaskInput{
-			Cluster:              aws.String(r.Cluster),
-			TaskDefinition:       aws.String(r.taskDefinitionArn(r)),
-			Overrides:            &taskOverride,
-			Count:                aws.Int32(r.taskCount()),
-			LaunchType:           ecsTypes.LaunchType(r.Target.LaunchType),
-			NetworkConfiguration: networkConfiguration,
-			PropagateTags:        propagateTags,
-		})
+func (r *Rule) removeTargets(ctx context.Context, svc *cloudwatchevents.Client, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	out, err := svc.RemoveTargets(ctx, &cloudwatchevents.RemoveTargetsInput{
+		Ids:          ids,
+		Rule:         aws.String(r.Name),
+		EventBusName: r.eventBusName(),
+	})
 	if err != nil {
 		return err
 	}
-	if len(out.Failures) > 0 {
-		f := out.Failures[0]
-		return fmt.Errorf("failed to Task. Arn: %q: %s", *f.Arn, *f.Reason)
+	if len(out.FailedEntries) > 0 {
+		f := out.FailedEntries[0]
+		return fmt.Errorf("failed to remove target %q from rule %q: %s",
+			aws.ToString(f.TargetId), r.Name, aws.ToString(f.ErrorMessage))
 	}
-	// TODO: Wait for task termination if `noWait` flag is false
-	//       (Is it necessary?)
 	return nil
 }
 
+// Run the rule
+func (r *Rule) Run(ctx context.Context, awsConf aws.Config, opts *RunOptions) error {
+	_, err := r.runTasks(ctx, awsConf, opts)
+	return err
+}
+
+// runTasks runs the rule and returns the ARNs of the started tasks
+func (r *Rule) runTasks(ctx context.Context, awsConf aws.Config, opts *RunOptions) ([]string, error) {
+	if err := r.validateEnv(); err != nil {
+		return nil, err
+	}
+	if err := r.validateTFstate(); err != nil {
+		return nil, err
+	}
+	if err := r.validateSSM(); err != nil {
+		return nil, err
+	}
+	if opts.DryRun {
+		return nil, r.dryRun(opts)
+	}
+	svc := ecs.NewFromConfig(awsConf, func(o *ecs.Options) {
+		o.Region = r.Region
+	})
+	logs := cloudwatchlogs.NewFromConfig(awsConf, func(o *cloudwatchlogs.Options) {
+		o.Region = r.Region
+	})
+	return r.run(ctx, svc, logs, opts)
+}
+
+// runTaskInput returns the input of RunTask equivalent to the one EventBridge sends for the target
+func (ta *Target) runTaskInput(r *Rule) (*ecs.RunTaskInput, error) {
+	return runTaskInput(ta.target(r))
+}
+
 // Delete the rule (maintained for backward compatibility)
 func (r *Rule) Delete(ctx context.Context, awsConf aws.Config, dryRun bool) error {
 	return r.deleteInternal(ctx, awsConf, dryRun, diffFormatPrettyColored)
@@ -606,6 +975,9 @@ func (r *Rule) Delete(ctx context.Context, awsConf aws.Config, dryRun bool) erro
 
 // deleteInternal is the internal implementation with configurable diff format
 func (r *Rule) deleteInternal(ctx context.Context, awsConf aws.Config, dryRun bool, format diffFormat) error {
+	if r.useScheduler() {
+		return r.deleteSchedule(ctx, awsConf, dryRun, format)
+	}
 	svc := cloudwatchevents.NewFromConfig(awsConf, func(o *cloudwatchevents.Options) {
 		o.Region = r.Region
 	})
@@ -619,42 +991,66 @@ func (r *Rule) deleteInternal(ctx context.Context, awsConf aws.Config, dryRun bo
 		dryRunSuffix = " (dry-run)"
 	}
 
-	diffOutput := formatDiff(r.Name, string(remoteRuleYaml), "", format)
+	diffOutput := formatDiff(r.Name, string(remoteRuleYaml), "", format, explainOptions{})
 	log.Printf("🪓 deleting following rule%s\n%s", dryRunSuffix, diffOutput)
 
 	if dryRun {
 		return nil
 	}
 
-	// Before deleting the rule, need to delete all targets.
-	if _, err := svc.RemoveTargets(ctx, &cloudwatchevents.RemoveTargetsInput{
-		Ids:  []string{r.targetID(r)},
-		Rule: aws.String(r.Name),
-	}); err != nil {
+	// Before deleting the rule, need to delete all targets including ones not managed
+	// by ecschedule.
+	targets, err := r.remoteTargets(ctx, svc)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for _, t := range targets {
+		ids = append(ids, aws.ToString(t.Id))
+	}
+	if err := r.removeTargets(ctx, svc, ids); err != nil {
 		return err
 	}
 	_, err = svc.DeleteRule(ctx, &cloudwatchevents.DeleteRuleInput{
-		Name: aws.String(r.Name),
+		Name:         aws.String(r.Name),
+		EventBusName: r.eventBusName(),
 	})
 	return err
 }
 
 // localYAMLForDiff returns the YAML representation of r used as the "to" side
-// of (*Rule).diff. It strips BaseConfig and normalizes an empty Role to
-// defaultRole so the output stays symmetric with the YAML reconstructed from
-// the remote rule (which always carries a resolved role).
+// of (*Rule).diff. It strips BaseConfig, puts targets in their canonical form
+// and normalizes an empty Role to defaultRole so the output stays symmetric
+// with the YAML reconstructed from the remote rule (which always carries a
+// resolved role).
 func (r *Rule) localYAMLForDiff() (string, error) {
-	bc := r.BaseConfig
-	r.BaseConfig = nil
-	defer func() { r.BaseConfig = bc }()
-
-	origRole := r.Role
-	if r.Role == "" {
-		r.Role = defaultRole
+	var targets []*Target
+	for _, ta := range r.targets() {
+		t := *ta
+		if t.Role == "" {
+			t.Role = defaultRole
+		}
+		if t.TargetCluster != "" && r.BaseConfig != nil && t.targetARN(r) == (&Target{}).targetARN(r) {
+			t.TargetCluster = ""
+		}
+		if t.TaskOverride != nil && r.BaseConfig != nil {
+			t.TaskOverride = t.TaskOverride.withRoleNames(fmt.Sprintf("arn:aws:iam::%s:role/", r.AccountID))
+		}
+		t.RetryPolicy = t.RetryPolicy.normalize()
+		targets = append(targets, &t)
+	}
+	lr := *r
+	lr.setTargets(targets)
+	lr.BaseConfig = nil
+	lr.ExpectedDuration = ""
+	lr.normalizeSchedule()
+	pattern, err := r.canonicalEventPattern()
+	if err != nil {
+		return "", err
 	}
-	defer func() { r.Role = origRole }()
+	lr.EventPattern = pattern
 
-	bs, err := yaml.Marshal(r)
+	bs, err := yaml.Marshal(&lr)
 	if err != nil {
 		return "", err
 	}
@@ -662,30 +1058,42 @@ func (r *Rule) localYAMLForDiff() (string, error) {
 }
 
 func (r *Rule) diff(ctx context.Context, cw *cloudwatchevents.Client) (from, to string, err error) {
+	from, to, _, err = r.diffTargets(ctx, cw)
+	return from, to, err
+}
+
+// diffTargets returns the diff of the rule and the IDs of the stale targets which apply removes
+func (r *Rule) diffTargets(ctx context.Context, cw *cloudwatchevents.Client) (from, to string, staleIDs []string, err error) {
 	rule := aws.String(r.Name)
 
 	c := r.BaseConfig
 
+	sc, err := r.keepOutOfBandState(ctx, cw)
+	if err != nil {
+		return "", "", nil, err
+	}
 	localRuleYaml, err := r.localYAMLForDiff()
 	if err != nil {
-		return "", "", err
+		return "", "", nil, err
 	}
 
 	ruleList, err := cw.ListRules(ctx, &cloudwatchevents.ListRulesInput{
-		NamePrefix: rule,
+		NamePrefix:   rule,
+		EventBusName: r.eventBusName(),
 	})
 	if err != nil {
-		return "", "", err
+		return "", "", nil, err
 	}
 
 	var (
 		roleArnPrefix = fmt.Sprintf("arn:aws:iam::%s:role/", c.AccountID)
 		rg            = &ruleGetter{
 			svc:              cw,
-			ruleArnPrefix:    fmt.Sprintf("arn:aws:events:%s:%s:rule/", c.Region, c.AccountID),
+			ruleArnPrefix:    ruleArnPrefix(c.Region, c.AccountID, c.EventBusName),
 			clusterArn:       fmt.Sprintf("arn:aws:ecs:%s:%s:cluster/%s", c.Region, c.AccountID, c.Cluster),
 			taskDefArnPrefix: fmt.Sprintf("arn:aws:ecs:%s:%s:task-definition/", c.Region, c.AccountID),
 			roleArnPrefix:    roleArnPrefix,
+			sqsArnPrefix:     fmt.Sprintf("arn:aws:sqs:%s:%s:", c.Region, c.AccountID),
 		}
 		remoteRuleYaml string
 	)
@@ -695,18 +1103,24 @@ func (r *Rule) diff(ctx context.Context, cw *cloudwatchevents.Client) (from, to
 		}
 		ru, err := rg.getRule(ctx, &r)
 		if err != nil {
-			return "", "", err
+			return "", "", nil, err
 		}
 		if ru != nil {
 			bs, err := yaml.Marshal(ru)
 			if err != nil {
-				return "", "", err
+				return "", "", nil, err
 			}
 			remoteRuleYaml = string(bs)
 			break
 		}
 	}
-	return remoteRuleYaml, localRuleYaml, nil
+	remoteTargets, err := r.remoteTargets(ctx, cw)
+	if err != nil {
+		return "", "", nil, err
+	}
+	staleIDs = r.staleTargetIDs(remoteTargets)
+	from, to = r.annotateScheduleTimezone(remoteRuleYaml, localRuleYaml)
+	return annotatePause(from, sc), annotateStaleTargets(annotatePause(to, sc), staleIDs), staleIDs, nil
 }
 
 // diffFormat represents the format of diff output
@@ -718,10 +1132,11 @@ const (
 )
 
 // formatDiff formats diff output in the specified format (without header for Unified)
-func formatDiff(ruleName, from, to string, format diffFormat) string {
+func formatDiff(ruleName, from, to string, format diffFormat, eo explainOptions) string {
 	if from == to {
 		return ""
 	}
+	from, to = annotateSchedules(from, eo), annotateSchedules(to, eo)
 
 	switch format {
 	case diffFormatUnified:
@@ -22,108 +22,29 @@ func (rg *ruleGetter) getRule(ctx context.Context, r *cweTypes.Rule) (*Rule, err
 	}
 
 	ta, err := rg.svc.ListTargetsByRule(ctx, &cloudwatchevents.ListTargetsByRuleInput{
-		Rule: r.Name,
+		Rule:         r.Name,
+		EventBusName: r.EventBusName,
 	})
 	if err != nil {
 		return nil, err
 	}
-	var targets []*Target
+	var (
+		targets   []*Target
+		onCluster bool
+	)
 	for _, t := range ta.Targets {
-		if *t.Arn != rg.clusterArn {
-			return nil, nil
-		}
-		targetID := *t.Id
-		if targetID == *r.Name {
-			targetID = ""
+		target, err := rg.getTarget(*r.Name, &t)
+		if err != nil {
+			return nil, err
 		}
-		ecsParams := t.EcsParameters
-		if ecsParams == nil {
+		if target == nil {
 			// ignore rule which have some non ecs targets
 			return nil, nil
 		}
-		target := &Target{TargetID: targetID}
-
-		// Always populate Role to keep the dumped/remote YAML symmetric with
-		// the local configuration, which always specifies a role explicitly
-		// (or implicitly via the default).
-		target.Role = strings.TrimPrefix(*t.RoleArn, rg.roleArnPrefix)
-
-		taskCount := *ecsParams.TaskCount
-		if taskCount != 1 {
-			target.TaskCount = taskCount
-		}
-		target.TaskDefinition = strings.TrimPrefix(*ecsParams.TaskDefinitionArn, rg.taskDefArnPrefix)
-
-		target.Group = aws.ToString(ecsParams.Group)
-		target.LaunchType = string(ecsParams.LaunchType)
-
-		var capacityProviderStrategy []*CapacityProviderStrategyItem
-		for _, cps := range ecsParams.CapacityProviderStrategy {
-			capacityProviderStrategy = append(capacityProviderStrategy, &CapacityProviderStrategyItem{
-				Base:             cps.Base,
-				Weight:           cps.Weight,
-				CapacityProvider: aws.ToString(cps.CapacityProvider),
-			})
-		}
-
-		target.CapacityProviderStrategy = capacityProviderStrategy
-
-		target.PlatformVersion = aws.ToString(ecsParams.PlatformVersion)
-		target.PropagateTags = aws.String(string(t.EcsParameters.PropagateTags))
-		if aws.ToString(target.PropagateTags) == "" {
-			target.PropagateTags = nil
-		}
-		if nc := ecsParams.NetworkConfiguration; nc != nil {
-			target.NetworkConfiguration = &NetworkConfiguration{
-				AwsVpcConfiguration: &AwsVpcConfiguration{
-					Subnets:        nc.AwsvpcConfiguration.Subnets,
-					SecurityGroups: nc.AwsvpcConfiguration.SecurityGroups,
-					AssignPublicIP: string(nc.AwsvpcConfiguration.AssignPublicIp),
-				},
-			}
-		}
-
-		// For backward-compatibility, ContainerOverrides and TaskOverride are held as separate fields.
-		taskOv := &ecsTypes.TaskOverride{}
-		if t.Input != nil {
-			if err := json.Unmarshal([]byte(*t.Input), taskOv); err != nil {
-				return nil, err
-			}
-			// Only set TaskOverride if CPU or Memory is specified to avoid empty diffs
-			if taskOv.Cpu != nil || taskOv.Memory != nil {
-				target.TaskOverride = &TaskOverride{
-					Cpu:    taskOv.Cpu,
-					Memory: taskOv.Memory,
-				}
-			}
-			var contOverrides []*ContainerOverride
-			for _, co := range taskOv.ContainerOverrides {
-				var cmd []string
-				for _, c := range co.Command {
-					cmd = append(cmd, c)
-				}
-				env := map[string]string{}
-				for _, kv := range co.Environment {
-					env[*kv.Name] = *kv.Value
-				}
-				contOverrides = append(contOverrides, &ContainerOverride{
-					Name:              *co.Name,
-					Command:           cmd,
-					Environment:       env,
-					Cpu:               co.Cpu,
-					Memory:            co.Memory,
-					MemoryReservation: co.MemoryReservation,
-				})
-			}
-			target.ContainerOverrides = contOverrides
+		if target.TargetCluster == "" {
+			onCluster = true
 		}
 		targets = append(targets, target)
-
-		if dlc := t.DeadLetterConfig; dlc != nil {
-			target.DeadLetterConfig = &DeadLetterConfig{
-				Sqs: strings.TrimPrefix(*dlc.Arn, rg.sqsArnPrefix),
-			}
-		}
 	}
 	var desc string
 	if r.Description != nil {
@@ -139,16 +60,186 @@ func (rg *ruleGetter) getRule(ctx context.Context, r *cweTypes.Rule) (*Rule, err
 		ScheduleExpression: expr,
 		Disabled:           string(r.State) == "DISABLED",
 	}
-	switch len(targets) {
-	case 0:
-		return nil, nil
-	case 1:
-		ru.Target = targets[0]
-	default:
-		// not supported multiple target yet
+	if r.EventPattern != nil {
+		pattern, err := parseEventPattern(*r.EventPattern)
+		if err != nil {
+			return nil, err
+		}
+		ru.EventPattern = pattern
+	}
+	// ignore rule which has no targets on the cluster
+	if !onCluster {
 		return nil, nil
-		// ru.Target = targets[0]
-		// ru.Targets = targets[1:]
 	}
+	ru.setTargets(targets)
 	return ru, nil
 }
+
+// clusterName trims the ARN prefix off the cluster ARN when the cluster is in the same
+// account and region as the configured one.
+func (rg *ruleGetter) clusterName(clusterArn string) string {
+	prefix := rg.clusterArn[:strings.LastIndex(rg.clusterArn, "/")+1]
+	return strings.TrimPrefix(clusterArn, prefix)
+}
+
+// roleName trims the ARN prefix off the role ARN when the role is in the same account
+func (rg *ruleGetter) roleName(roleArn *string) *string {
+	if roleArn == nil {
+		return nil
+	}
+	return aws.String(strings.TrimPrefix(*roleArn, rg.roleArnPrefix))
+}
+
+// getTarget converts a remote target into Target. It returns nil for non ECS targets.
+func (rg *ruleGetter) getTarget(ruleName string, t *cweTypes.Target) (*Target, error) {
+	ecsParams := t.EcsParameters
+	if ecsParams == nil {
+		return nil, nil
+	}
+	targetID := aws.ToString(t.Id)
+	if targetID == ruleName {
+		targetID = ""
+	}
+	target := &Target{TargetID: targetID}
+	if *t.Arn != rg.clusterArn {
+		target.TargetCluster = rg.clusterName(*t.Arn)
+	}
+
+	// Always populate Role to keep the dumped/remote YAML symmetric with
+	// the local configuration, which always specifies a role explicitly
+	// (or implicitly via the default).
+	target.Role = strings.TrimPrefix(*t.RoleArn, rg.roleArnPrefix)
+
+	taskCount := *ecsParams.TaskCount
+	if taskCount != 1 {
+		target.TaskCount = taskCount
+	}
+	target.TaskDefinition = strings.TrimPrefix(*ecsParams.TaskDefinitionArn, rg.taskDefArnPrefix)
+
+	target.Group = aws.ToString(ecsParams.Group)
+	target.LaunchType = string(ecsParams.LaunchType)
+
+	var capacityProviderStrategy []*CapacityProviderStrategyItem
+	for _, cps := range ecsParams.CapacityProviderStrategy {
+		capacityProviderStrategy = append(capacityProviderStrategy, &CapacityProviderStrategyItem{
+			Base:             cps.Base,
+			Weight:           cps.Weight,
+			CapacityProvider: aws.ToString(cps.CapacityProvider),
+		})
+	}
+
+	target.CapacityProviderStrategy = capacityProviderStrategy
+
+	target.PlatformVersion = aws.ToString(ecsParams.PlatformVersion)
+	target.PropagateTags = aws.String(string(t.EcsParameters.PropagateTags))
+	if aws.ToString(target.PropagateTags) == "" {
+		target.PropagateTags = nil
+	}
+	if nc := ecsParams.NetworkConfiguration; nc != nil {
+		target.NetworkConfiguration = &NetworkConfiguration{
+			AwsVpcConfiguration: &AwsVpcConfiguration{
+				Subnets:        nc.AwsvpcConfiguration.Subnets,
+				SecurityGroups: nc.AwsvpcConfiguration.SecurityGroups,
+				AssignPublicIP: string(nc.AwsvpcConfiguration.AssignPublicIp),
+			},
+		}
+	}
+
+	for _, pc := range ecsParams.PlacementConstraints {
+		target.PlacementConstraints = append(target.PlacementConstraints, &PlacementConstraint{
+			Type:       string(pc.Type),
+			Expression: aws.ToString(pc.Expression),
+		})
+	}
+	for _, ps := range ecsParams.PlacementStrategy {
+		target.PlacementStrategy = append(target.PlacementStrategy, &PlacementStrategy{
+			Type:  string(ps.Type),
+			Field: aws.ToString(ps.Field),
+		})
+	}
+	target.EnableECSManagedTags = ecsParams.EnableECSManagedTags
+	target.EnableExecuteCommand = ecsParams.EnableExecuteCommand
+	target.ReferenceID = aws.ToString(ecsParams.ReferenceId)
+	if len(ecsParams.Tags) > 0 {
+		target.Tags = map[string]string{}
+		for _, tag := range ecsParams.Tags {
+			target.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+
+	// For backward-compatibility, ContainerOverrides and TaskOverride are held as separate fields.
+	taskOv := &ecsTypes.TaskOverride{}
+	if t.Input != nil {
+		if err := json.Unmarshal([]byte(*t.Input), taskOv); err != nil {
+			return nil, err
+		}
+		// Only set TaskOverride if any field is specified to avoid empty diffs
+		to := &TaskOverride{
+			Cpu:              taskOv.Cpu,
+			Memory:           taskOv.Memory,
+			TaskRoleArn:      rg.roleName(taskOv.TaskRoleArn),
+			ExecutionRoleArn: rg.roleName(taskOv.ExecutionRoleArn),
+		}
+		if es := taskOv.EphemeralStorage; es != nil {
+			to.EphemeralStorage = &EphemeralStorage{SizeInGiB: es.SizeInGiB}
+		}
+		for _, iao := range taskOv.InferenceAcceleratorOverrides {
+			to.InferenceAcceleratorOverrides = append(to.InferenceAcceleratorOverrides, &InferenceAcceleratorOverride{
+				DeviceName: aws.ToString(iao.DeviceName),
+				DeviceType: aws.ToString(iao.DeviceType),
+			})
+		}
+		if !to.isEmpty() {
+			target.TaskOverride = to
+		}
+		var contOverrides []*ContainerOverride
+		for _, co := range taskOv.ContainerOverrides {
+			var cmd []string
+			for _, c := range co.Command {
+				cmd = append(cmd, c)
+			}
+			env := map[string]string{}
+			for _, kv := range co.Environment {
+				env[*kv.Name] = *kv.Value
+			}
+			var envFiles []*EnvironmentFile
+			for _, ef := range co.EnvironmentFiles {
+				envFiles = append(envFiles, &EnvironmentFile{
+					Type:  string(ef.Type),
+					Value: aws.ToString(ef.Value),
+				})
+			}
+			var resourceRequirements []*ResourceRequirement
+			for _, rr := range co.ResourceRequirements {
+				resourceRequirements = append(resourceRequirements, &ResourceRequirement{
+					Type:  string(rr.Type),
+					Value: aws.ToString(rr.Value),
+				})
+			}
+			contOverrides = append(contOverrides, &ContainerOverride{
+				Name:                 *co.Name,
+				Command:              cmd,
+				Environment:          env,
+				EnvironmentFiles:     envFiles,
+				Cpu:                  co.Cpu,
+				Memory:               co.Memory,
+				MemoryReservation:    co.MemoryReservation,
+				ResourceRequirements: resourceRequirements,
+			})
+		}
+		target.ContainerOverrides = contOverrides
+	}
+
+	if dlc := t.DeadLetterConfig; dlc != nil {
+		target.DeadLetterConfig = &DeadLetterConfig{
+			Sqs: strings.TrimPrefix(*dlc.Arn, rg.sqsArnPrefix),
+		}
+	}
+	if rp := t.RetryPolicy; rp != nil {
+		target.RetryPolicy = (&RetryPolicy{
+			MaximumRetryAttempts:     rp.MaximumRetryAttempts,
+			MaximumEventAgeInSeconds: rp.MaximumEventAgeInSeconds,
+		}).normalize()
+	}
+	return target, nil
+}
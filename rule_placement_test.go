@@ -0,0 +1,104 @@
+package ecschedule
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestRule_Placement(t *testing.T) {
+	r := &Rule{
+		Name:               "hoge",
+		ScheduleExpression: "rate(1 hour)",
+		Target: &Target{
+			TaskDefinition: "task1",
+			LaunchType:     "EC2",
+			PlacementConstraints: []*PlacementConstraint{
+				{Type: "memberOf", Expression: "attribute:ecs.instance-type =~ c5.*"},
+				{Type: "distinctInstance"},
+			},
+			PlacementStrategy: []*PlacementStrategy{
+				{Type: "spread", Field: "attribute:ecs.availability-zone"},
+				{Type: "binpack", Field: "memory"},
+			},
+		},
+		BaseConfig: &BaseConfig{
+			Region:    "us-east-1",
+			Cluster:   "api",
+			AccountID: "339",
+		},
+	}
+	if err := r.Target.validatePlacement(); err != nil {
+		t.Fatalf("error should be nil, but: %s", err)
+	}
+
+	p := r.PutTargetsInput().Targets[0].EcsParameters
+	if len(p.PlacementConstraints) != 2 || p.PlacementConstraints[1].Expression != nil {
+		t.Errorf("unexpected placement constraints: %+v", p.PlacementConstraints)
+	}
+	if len(p.PlacementStrategy) != 2 || aws.ToString(p.PlacementStrategy[1].Field) != "memory" {
+		t.Errorf("unexpected placement strategy: %+v", p.PlacementStrategy)
+	}
+
+	in, err := r.Target.runTaskInput(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(in.PlacementConstraints) != 2 || len(in.PlacementStrategy) != 2 {
+		t.Errorf("placement should be passed to RunTask: %+v, %+v", in.PlacementConstraints, in.PlacementStrategy)
+	}
+
+	local, remote := roundTripTarget(t, r)
+	if local != remote {
+		t.Errorf("placement should round-trip\nlocal:\n%s\nremote:\n%s", local, remote)
+	}
+}
+
+func TestTarget_validatePlacement(t *testing.T) {
+	testCases := []struct {
+		name   string
+		target *Target
+		expect string
+	}{{
+		name: "fargate",
+		target: &Target{LaunchType: "FARGATE", PlacementStrategy: []*PlacementStrategy{
+			{Type: "random"},
+		}},
+		expect: "placementConstraints and placementStrategy are not available with the FARGATE launch type",
+	}, {
+		name: "memberOf without expression",
+		target: &Target{PlacementConstraints: []*PlacementConstraint{
+			{Type: "memberOf"},
+		}},
+		expect: "expression is required for the memberOf placement constraint",
+	}, {
+		name: "unknown constraint",
+		target: &Target{PlacementConstraints: []*PlacementConstraint{
+			{Type: "sameInstance"},
+		}},
+		expect: `invalid placement constraint type "sameInstance"`,
+	}, {
+		name: "spread without field",
+		target: &Target{PlacementStrategy: []*PlacementStrategy{
+			{Type: "spread"},
+		}},
+		expect: "field is required for the spread placement strategy",
+	}, {
+		name: "binpack by instanceId",
+		target: &Target{PlacementStrategy: []*PlacementStrategy{
+			{Type: "binpack", Field: "instanceId"},
+		}},
+		expect: `field of the binpack placement strategy must be cpu or memory, but: "instanceId"`,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.target.validatePlacement()
+			if err == nil {
+				t.Fatalf("error should be occurred, but nil")
+			}
+			if g := err.Error(); g != tc.expect {
+				t.Errorf("error should be %q, but: %q", tc.expect, g)
+			}
+		})
+	}
+}
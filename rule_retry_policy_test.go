@@ -0,0 +1,70 @@
+package ecschedule
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestRule_RetryPolicy(t *testing.T) {
+	r := &Rule{
+		Name:               "hoge",
+		ScheduleExpression: "rate(1 hour)",
+		Target: &Target{
+			TaskDefinition: "task1",
+			RetryPolicy: &RetryPolicy{
+				MaximumRetryAttempts:     aws.Int32(3),
+				MaximumEventAgeInSeconds: aws.Int32(defaultMaximumEventAgeInSeconds),
+			},
+		},
+		BaseConfig: &BaseConfig{
+			Region:    "us-east-1",
+			Cluster:   "api",
+			AccountID: "339",
+		},
+	}
+	rp := r.PutTargetsInput().Targets[0].RetryPolicy
+	if rp == nil || aws.ToInt32(rp.MaximumRetryAttempts) != 3 || aws.ToInt32(rp.MaximumEventAgeInSeconds) != 86400 {
+		t.Errorf("unexpected retry policy: %+v", rp)
+	}
+
+	local, remote := roundTripTarget(t, r)
+	if local != remote {
+		t.Errorf("retry policy should round-trip\nlocal:\n%s\nremote:\n%s", local, remote)
+	}
+
+	// the remote target returns the defaults when the retry policy is not specified
+	rg := testRuleGetter(r.BaseConfig)
+	target := r.Target.target(r)
+	target.RetryPolicy.MaximumRetryAttempts = aws.Int32(defaultMaximumRetryAttempts)
+	rt, err := rg.getTarget(r.Name, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt.RetryPolicy != nil {
+		t.Errorf("retry policy with the defaults should be omitted, but: %+v", rt.RetryPolicy)
+	}
+}
+
+func TestRetryPolicy_validate(t *testing.T) {
+	testCases := []struct {
+		rp     *RetryPolicy
+		expect string
+	}{
+		{&RetryPolicy{MaximumRetryAttempts: aws.Int32(0), MaximumEventAgeInSeconds: aws.Int32(60)}, ""},
+		{&RetryPolicy{MaximumRetryAttempts: aws.Int32(186)}, "retryPolicy.maximumRetryAttempts must be between 0 and 185"},
+		{&RetryPolicy{MaximumEventAgeInSeconds: aws.Int32(59)}, "retryPolicy.maximumEventAgeInSeconds must be between 60 and 86400"},
+	}
+	for _, tc := range testCases {
+		err := tc.rp.validate()
+		if tc.expect == "" {
+			if err != nil {
+				t.Errorf("error should be nil, but: %s", err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tc.expect {
+			t.Errorf("error should be %q, but: %v", tc.expect, err)
+		}
+	}
+}
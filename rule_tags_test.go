@@ -0,0 +1,54 @@
+package ecschedule
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestRule_TaskTags(t *testing.T) {
+	r := &Rule{
+		Name:               "hoge",
+		ScheduleExpression: "rate(1 hour)",
+		Target: &Target{
+			TaskDefinition:       "task1",
+			EnableECSManagedTags: true,
+			EnableExecuteCommand: true,
+			ReferenceID:          "nightly",
+			Tags: map[string]string{
+				"team":        "backend",
+				"cost-center": "1234",
+			},
+		},
+		BaseConfig: &BaseConfig{
+			Region:    "us-east-1",
+			Cluster:   "api",
+			AccountID: "339",
+		},
+	}
+
+	p := r.PutTargetsInput().Targets[0].EcsParameters
+	if !p.EnableECSManagedTags || !p.EnableExecuteCommand || aws.ToString(p.ReferenceId) != "nightly" {
+		t.Errorf("unexpected ecs parameters: %+v", p)
+	}
+	if len(p.Tags) != 2 || aws.ToString(p.Tags[0].Key) != "cost-center" {
+		t.Errorf("tags should be sorted by key: %+v", p.Tags)
+	}
+
+	in, err := r.Target.runTaskInput(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !in.EnableExecuteCommand || !in.EnableECSManagedTags || len(in.Tags) != 2 || aws.ToString(in.ReferenceId) != "nightly" {
+		t.Errorf("tags and execute command should be passed to RunTask: %+v", in)
+	}
+
+	local, remote := roundTripTarget(t, r)
+	if local != remote {
+		t.Errorf("task tags should round-trip\nlocal:\n%s\nremote:\n%s", local, remote)
+	}
+	if !strings.Contains(local, "team: backend") {
+		t.Errorf("tags should be shown in diffs:\n%s", local)
+	}
+}
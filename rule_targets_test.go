@@ -0,0 +1,196 @@
+package ecschedule
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cweTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchevents/types"
+	"github.com/goccy/go-yaml"
+)
+
+func TestRule_PutTargetsInput_MultipleTargets(t *testing.T) {
+	r := &Rule{
+		Name:               "fanout",
+		ScheduleExpression: "cron(0 0 * * ? *)",
+		Target: &Target{
+			TaskDefinition: "task1:1",
+		},
+		Targets: []*Target{
+			{
+				TargetID:       "fanout-batch",
+				TargetCluster:  "batch",
+				TaskDefinition: "task2:3",
+				Role:           "batchEventsRole",
+			},
+		},
+		BaseConfig: &BaseConfig{
+			Region:    "ap-northeast-1",
+			Cluster:   "api",
+			AccountID: "123456789012",
+		},
+	}
+
+	input := r.PutTargetsInput()
+	if len(input.Targets) != 2 {
+		t.Fatalf("expected 2 targets, got %d", len(input.Targets))
+	}
+
+	type target struct{ id, arn, taskDef, role string }
+	var got []target
+	for _, ta := range input.Targets {
+		got = append(got, target{
+			id:      aws.ToString(ta.Id),
+			arn:     aws.ToString(ta.Arn),
+			taskDef: aws.ToString(ta.EcsParameters.TaskDefinitionArn),
+			role:    aws.ToString(ta.RoleArn),
+		})
+	}
+	expect := []target{
+		{
+			id:      "fanout",
+			arn:     "arn:aws:ecs:ap-northeast-1:123456789012:cluster/api",
+			taskDef: "arn:aws:ecs:ap-northeast-1:123456789012:task-definition/task1:1",
+			role:    "arn:aws:iam::123456789012:role/ecsEventsRole",
+		},
+		{
+			id:      "fanout-batch",
+			arn:     "arn:aws:ecs:ap-northeast-1:123456789012:cluster/batch",
+			taskDef: "arn:aws:ecs:ap-northeast-1:123456789012:task-definition/task2:3",
+			role:    "arn:aws:iam::123456789012:role/batchEventsRole",
+		},
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("unexpected targets\nwant: %+v\ngot:  %+v", expect, got)
+	}
+}
+
+func TestRule_LocalYAMLForDiff_CanonicalTargets(t *testing.T) {
+	bc := &BaseConfig{
+		Region:    "ap-northeast-1",
+		Cluster:   "api",
+		AccountID: "123456789012",
+	}
+
+	inline := &Rule{
+		Name:               "fanout",
+		ScheduleExpression: "cron(0 0 * * ? *)",
+		Target: &Target{
+			TargetID:       "b",
+			TaskDefinition: "task2:1",
+		},
+		Targets: []*Target{
+			{TargetID: "a", TaskDefinition: "task1:1", TargetCluster: "api"},
+		},
+		BaseConfig: bc,
+	}
+	listed := &Rule{
+		Name:               "fanout",
+		ScheduleExpression: "cron(0 0 * * ? *)",
+		Targets: []*Target{
+			{TargetID: "a", TaskDefinition: "task1:1"},
+			{TargetID: "b", TaskDefinition: "task2:1"},
+		},
+		BaseConfig: bc,
+	}
+
+	gotInline, err := inline.localYAMLForDiff()
+	if err != nil {
+		t.Fatalf("localYAMLForDiff (inline): %v", err)
+	}
+	gotListed, err := listed.localYAMLForDiff()
+	if err != nil {
+		t.Fatalf("localYAMLForDiff (listed): %v", err)
+	}
+	if gotInline != gotListed {
+		t.Errorf("YAML for inline and listed targets should match.\ninline:\n%s\nlisted:\n%s", gotInline, gotListed)
+	}
+	if inline.Target == nil || inline.Target.TargetID != "b" || len(inline.Targets) != 1 {
+		t.Errorf("localYAMLForDiff should not modify the rule: %+v", inline)
+	}
+}
+
+func TestRule_StaleTargetIDs(t *testing.T) {
+	r := &Rule{
+		Name: "fanout",
+		Target: &Target{
+			TaskDefinition: "task1:1",
+		},
+		Targets: []*Target{
+			{TargetID: "fanout-batch", TaskDefinition: "task2:1"},
+		},
+		BaseConfig: &BaseConfig{Region: "us-east-1", Cluster: "api", AccountID: "339"},
+	}
+	ecsTarget := func(id, cluster string) cweTypes.Target {
+		return cweTypes.Target{
+			Id:            aws.String(id),
+			Arn:           aws.String("arn:aws:ecs:us-east-1:339:cluster/" + cluster),
+			EcsParameters: &cweTypes.EcsParameters{},
+		}
+	}
+	remote := []cweTypes.Target{
+		ecsTarget("fanout", "api"),
+		ecsTarget("fanout-old", "api"),
+		ecsTarget("fanout-batch", "api"),
+		ecsTarget("other-cluster", "batch"),
+		{Id: aws.String("manual"), Arn: aws.String("arn:aws:lambda:us-east-1:339:function:notify")},
+	}
+
+	got := r.staleTargetIDs(remote)
+	expect := []string{"fanout-old"}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("staleTargetIDs() = %v, want %v", got, expect)
+	}
+
+	if got := r.staleTargetIDs(remote[:1]); got != nil {
+		t.Errorf("staleTargetIDs() = %v, want nil", got)
+	}
+}
+
+func TestAnnotateStaleTargets(t *testing.T) {
+	ruleYAML := "name: fanout\n"
+	if got := annotateStaleTargets(ruleYAML, nil); got != ruleYAML {
+		t.Errorf("rule YAML should be left as it is, but: %q", got)
+	}
+	want := "name: fanout\n# stale targets to be removed: fanout-old, fanout-tmp\n"
+	if got := annotateStaleTargets(ruleYAML, []string{"fanout-old", "fanout-tmp"}); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// testRuleGetter returns the ruleGetter of the CloudWatch Events rules as diff builds it, without
+// the client
+func testRuleGetter(bc *BaseConfig) *ruleGetter {
+	return &ruleGetter{
+		ruleArnPrefix:    ruleArnPrefix(bc.Region, bc.AccountID, bc.EventBusName),
+		clusterArn:       fmt.Sprintf("arn:aws:ecs:%s:%s:cluster/%s", bc.Region, bc.AccountID, bc.Cluster),
+		taskDefArnPrefix: fmt.Sprintf("arn:aws:ecs:%s:%s:task-definition/", bc.Region, bc.AccountID),
+		roleArnPrefix:    fmt.Sprintf("arn:aws:iam::%s:role/", bc.AccountID),
+		sqsArnPrefix:     fmt.Sprintf("arn:aws:sqs:%s:%s:", bc.Region, bc.AccountID),
+	}
+}
+
+// roundTripTarget returns the YAML of the first target of the rule as it is compared in
+// diffs, and the YAML of the same target read back from the parameters sent to the API.
+func roundTripTarget(t *testing.T, r *Rule) (local, remote string) {
+	t.Helper()
+	ta := r.targets()[0]
+	rt, err := testRuleGetter(r.BaseConfig).getTarget(r.Name, ta.target(r))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lr := *r
+	lr.setTargets([]*Target{ta})
+	l, err := lr.localYAMLForDiff()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := &Rule{Name: r.Name, ScheduleExpression: r.ScheduleExpression, EventPattern: r.EventPattern, Disabled: r.Disabled}
+	rr.setTargets([]*Target{rt})
+	bs, err := yaml.Marshal(rr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l, string(bs)
+}
@@ -0,0 +1,95 @@
+package ecschedule
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestRule_FullTaskOverride(t *testing.T) {
+	r := &Rule{
+		Name:               "hoge",
+		ScheduleExpression: "rate(1 hour)",
+		Target: &Target{
+			TaskDefinition: "task1",
+			TaskOverride: &TaskOverride{
+				TaskRoleArn:      aws.String("batchTaskRole"),
+				ExecutionRoleArn: aws.String("arn:aws:iam::999:role/executionRole"),
+				EphemeralStorage: &EphemeralStorage{SizeInGiB: 100},
+			},
+			ContainerOverrides: []*ContainerOverride{
+				{
+					Name:    "container1",
+					Command: []string{"subcmd"},
+					EnvironmentFiles: []*EnvironmentFile{
+						{Type: "s3", Value: "arn:aws:s3:::bucket/app.env"},
+					},
+					ResourceRequirements: []*ResourceRequirement{
+						{Type: "GPU", Value: "1"},
+					},
+				},
+			},
+		},
+		BaseConfig: &BaseConfig{
+			Region:    "us-east-1",
+			Cluster:   "api",
+			AccountID: "339",
+		},
+	}
+
+	input := aws.ToString(r.PutTargetsInput().Targets[0].Input)
+	for _, e := range []string{
+		`"taskRoleArn":"arn:aws:iam::339:role/batchTaskRole"`,
+		`"executionRoleArn":"arn:aws:iam::999:role/executionRole"`,
+		`"ephemeralStorage":{"sizeInGiB":100}`,
+		`"environmentFiles":[{"type":"s3","value":"arn:aws:s3:::bucket/app.env"}]`,
+		`"resourceRequirements":[{"type":"GPU","value":"1"}]`,
+	} {
+		if !strings.Contains(input, e) {
+			t.Errorf("input should contain %s, but: %s", e, input)
+		}
+	}
+
+	in, err := r.Target.runTaskInput(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ov := in.Overrides
+	if aws.ToString(ov.TaskRoleArn) != "arn:aws:iam::339:role/batchTaskRole" || ov.EphemeralStorage.SizeInGiB != 100 {
+		t.Errorf("unexpected task override of RunTask: %+v", ov)
+	}
+	if co := ov.ContainerOverrides[0]; len(co.EnvironmentFiles) != 1 || len(co.ResourceRequirements) != 1 {
+		t.Errorf("unexpected container override of RunTask: %+v", co)
+	}
+
+	local, remote := roundTripTarget(t, r)
+	if local != remote {
+		t.Errorf("task override should round-trip\nlocal:\n%s\nremote:\n%s", local, remote)
+	}
+}
+
+func TestRule_TaskOverrideRoleArnInTheAccount(t *testing.T) {
+	to := &TaskOverride{
+		TaskRoleArn:      aws.String("arn:aws:iam::339:role/batchTaskRole"),
+		ExecutionRoleArn: aws.String("arn:aws:iam::339:role/executionRole"),
+	}
+	r := &Rule{
+		Name:               "hoge",
+		ScheduleExpression: "rate(1 hour)",
+		Target:             &Target{TaskDefinition: "task1", TaskOverride: to},
+		BaseConfig: &BaseConfig{
+			Region:    "us-east-1",
+			Cluster:   "api",
+			AccountID: "339",
+		},
+	}
+	// the remote role ARNs in the account are read as the role names
+	local, remote := roundTripTarget(t, r)
+	if local != remote {
+		t.Errorf("role ARNs in the account should not show up in the diff\nlocal:\n%s\nremote:\n%s", local, remote)
+	}
+	if aws.ToString(to.TaskRoleArn) != "arn:aws:iam::339:role/batchTaskRole" {
+		t.Errorf("localYAMLForDiff should not modify the task override: %s", aws.ToString(to.TaskRoleArn))
+	}
+}
@@ -0,0 +1,339 @@
+package ecschedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cweTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchevents/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// ecsClient is the subset of the ECS API used by run. It is replaced with a fake in tests.
+type ecsClient interface {
+	RunTask(context.Context, *ecs.RunTaskInput, ...func(*ecs.Options)) (*ecs.RunTaskOutput, error)
+	DescribeTasks(context.Context, *ecs.DescribeTasksInput, ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error)
+	DescribeTaskDefinition(context.Context, *ecs.DescribeTaskDefinitionInput, ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error)
+	ListTasks(context.Context, *ecs.ListTasksInput, ...func(*ecs.Options)) (*ecs.ListTasksOutput, error)
+}
+
+// taskPollInterval is the interval to poll the status of the tasks started by run
+var taskPollInterval = 6 * time.Second
+
+// describeTasksLimit is the maximum number of tasks DescribeTasks accepts at once
+const describeTasksLimit = 100
+
+// runningTask is a task started by run
+type runningTask struct {
+	cluster string
+	arn     string
+}
+
+func (rt runningTask) id() string {
+	return rt.arn[strings.LastIndex(rt.arn, "/")+1:]
+}
+
+// taskFailedError is returned when an essential container of the tasks did not exit successfully.
+// The exit code of the command is the one of the first failed container.
+type taskFailedError struct {
+	exitCode int
+	msgs     []string
+}
+
+func (e *taskFailedError) Error() string {
+	return fmt.Sprintf("task failed:\n%s", strings.Join(e.msgs, "\n"))
+}
+
+// ExitCode returns the exit code of the failed container
+func (e *taskFailedError) ExitCode() int {
+	return e.exitCode
+}
+
+// RunOptions are options for running the rule
+type RunOptions struct {
+	// NoWait exits immediately after starting the tasks
+	NoWait bool
+	// Follow streams the awslogs logs of the containers to Out until the tasks stop
+	Follow bool
+	// DryRun writes the inputs of RunTask to Out as JSON instead of starting the tasks
+	DryRun    bool
+	Overrides *RunOverrides
+	// Concurrency is the policy when tasks of the rule are still running: allow, forbid or wait
+	Concurrency string
+	Out         io.Writer
+}
+
+// runTaskInputs returns the inputs of RunTask of all targets with the overrides merged
+func (r *Rule) runTaskInputs(opts *RunOptions) ([]*ecs.RunTaskInput, error) {
+	var inputs []*ecs.RunTaskInput
+	for _, ta := range r.targets() {
+		in, err := ta.runTaskInput(r)
+		if err != nil {
+			return nil, err
+		}
+		opts.Overrides.apply(in, r)
+		in.StartedBy = aws.String(r.startedBy())
+		inputs = append(inputs, in)
+	}
+	return inputs, nil
+}
+
+// dryRun writes the inputs of RunTask to opts.Out
+func (r *Rule) dryRun(opts *RunOptions) error {
+	inputs, err := r.runTaskInputs(opts)
+	if err != nil {
+		return err
+	}
+	for _, in := range inputs {
+		bs, err := runTaskInputJSON(in)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(opts.Out, string(bs))
+	}
+	return nil
+}
+
+// run starts tasks of all targets of the rule. Unless opts.NoWait is specified, it waits for all
+// the tasks to stop and returns an error when any essential container failed.
+// It returns the ARNs of the started tasks even when it fails after starting some of them.
+func (r *Rule) run(ctx context.Context, svc ecsClient, logs logsClient, opts *RunOptions) ([]string, error) {
+	var (
+		tasks    []runningTask
+		taskDefs = map[string]string{}
+	)
+	inputs, err := r.runTaskInputs(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkConcurrency(ctx, svc, inputs, opts.Concurrency); err != nil {
+		return nil, err
+	}
+	for _, in := range inputs {
+		out, err := svc.RunTask(ctx, in)
+		if err != nil {
+			return taskARNs(tasks), err
+		}
+		if len(out.Failures) > 0 {
+			f := out.Failures[0]
+			return taskARNs(tasks), fmt.Errorf("failed to Task. Arn: %q: %s", aws.ToString(f.Arn), aws.ToString(f.Reason))
+		}
+		for _, t := range out.Tasks {
+			log.Printf("started the task %s on the cluster %q", aws.ToString(t.TaskArn), aws.ToString(in.Cluster))
+			tasks = append(tasks, runningTask{cluster: aws.ToString(in.Cluster), arn: aws.ToString(t.TaskArn)})
+			taskDefs[aws.ToString(t.TaskArn)] = aws.ToString(t.TaskDefinitionArn)
+		}
+	}
+	arns := taskARNs(tasks)
+	if opts.NoWait {
+		return arns, nil
+	}
+	var lf *logFollower
+	if opts.Follow {
+		streams, err := taskLogStreams(ctx, svc, tasks, taskDefs)
+		if err != nil {
+			return arns, err
+		}
+		lf = newLogFollower(logs, opts.Out)
+		lf.follow(ctx, streams)
+	}
+	stopped, err := waitTasks(ctx, svc, tasks)
+	if lf != nil {
+		// flush the remaining logs before reporting the result
+		lf.stop()
+	}
+	if err != nil {
+		return arns, err
+	}
+	return arns, reportTasks(ctx, svc, stopped)
+}
+
+func taskARNs(tasks []runningTask) []string {
+	var arns []string
+	for _, t := range tasks {
+		arns = append(arns, t.arn)
+	}
+	return arns
+}
+
+// runTaskInput converts the target of CloudWatch Events into the input of RunTask in the same way
+// as EventBridge starts the task, so that run launches the task just like the schedule does.
+func runTaskInput(t *cweTypes.Target) (*ecs.RunTaskInput, error) {
+	in := &ecs.RunTaskInput{
+		Cluster: t.Arn,
+	}
+	if t.Input != nil {
+		ov := &ecsTypes.TaskOverride{}
+		if err := json.Unmarshal([]byte(*t.Input), ov); err != nil {
+			return nil, err
+		}
+		in.Overrides = ov
+	}
+	p := t.EcsParameters
+	if p == nil {
+		return in, nil
+	}
+	in.TaskDefinition = p.TaskDefinitionArn
+	in.Count = p.TaskCount
+	in.Group = p.Group
+	in.LaunchType = ecsTypes.LaunchType(p.LaunchType)
+	in.PlatformVersion = p.PlatformVersion
+	in.PropagateTags = ecsTypes.PropagateTags(p.PropagateTags)
+	in.ReferenceId = p.ReferenceId
+	in.EnableECSManagedTags = p.EnableECSManagedTags
+	in.EnableExecuteCommand = p.EnableExecuteCommand
+	for _, cps := range p.CapacityProviderStrategy {
+		in.CapacityProviderStrategy = append(in.CapacityProviderStrategy, ecsTypes.CapacityProviderStrategyItem{
+			CapacityProvider: cps.CapacityProvider,
+			Base:             cps.Base,
+			Weight:           cps.Weight,
+		})
+	}
+	if nc := p.NetworkConfiguration; nc != nil && nc.AwsvpcConfiguration != nil {
+		in.NetworkConfiguration = &ecsTypes.NetworkConfiguration{
+			AwsvpcConfiguration: &ecsTypes.AwsVpcConfiguration{
+				Subnets:        nc.AwsvpcConfiguration.Subnets,
+				SecurityGroups: nc.AwsvpcConfiguration.SecurityGroups,
+				AssignPublicIp: ecsTypes.AssignPublicIp(nc.AwsvpcConfiguration.AssignPublicIp),
+			},
+		}
+	}
+	for _, pc := range p.PlacementConstraints {
+		in.PlacementConstraints = append(in.PlacementConstraints, ecsTypes.PlacementConstraint{
+			Expression: pc.Expression,
+			Type:       ecsTypes.PlacementConstraintType(pc.Type),
+		})
+	}
+	for _, ps := range p.PlacementStrategy {
+		in.PlacementStrategy = append(in.PlacementStrategy, ecsTypes.PlacementStrategy{
+			Field: ps.Field,
+			Type:  ecsTypes.PlacementStrategyType(ps.Type),
+		})
+	}
+	for _, tag := range p.Tags {
+		in.Tags = append(in.Tags, ecsTypes.Tag{Key: tag.Key, Value: tag.Value})
+	}
+	return in, nil
+}
+
+// waitTasks polls the tasks until all of them are STOPPED
+func waitTasks(ctx context.Context, svc ecsClient, tasks []runningTask) ([]ecsTypes.Task, error) {
+	log.Printf("waiting for %d task(s) to stop", len(tasks))
+	for {
+		described, err := describeTasks(ctx, svc, tasks)
+		if err != nil {
+			return nil, err
+		}
+		stopped := true
+		for _, t := range described {
+			if aws.ToString(t.LastStatus) != string(ecsTypes.DesiredStatusStopped) {
+				stopped = false
+				break
+			}
+		}
+		if stopped {
+			return described, nil
+		}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, errors.New("timed out waiting for the tasks to stop")
+			}
+			return nil, ctx.Err()
+		case <-time.After(taskPollInterval):
+		}
+	}
+}
+
+func describeTasks(ctx context.Context, svc ecsClient, tasks []runningTask) ([]ecsTypes.Task, error) {
+	var (
+		arns     = map[string][]string{}
+		clusters []string
+	)
+	for _, t := range tasks {
+		if _, ok := arns[t.cluster]; !ok {
+			clusters = append(clusters, t.cluster)
+		}
+		arns[t.cluster] = append(arns[t.cluster], t.arn)
+	}
+	var described []ecsTypes.Task
+	for _, cluster := range clusters {
+		for ids := arns[cluster]; len(ids) > 0; {
+			n := min(len(ids), describeTasksLimit)
+			out, err := svc.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+				Cluster: aws.String(cluster),
+				Tasks:   ids[:n],
+			})
+			if err != nil {
+				return nil, err
+			}
+			if len(out.Failures) > 0 {
+				f := out.Failures[0]
+				return nil, fmt.Errorf("failed to describe the task %q: %s", aws.ToString(f.Arn), aws.ToString(f.Reason))
+			}
+			described = append(described, out.Tasks...)
+			ids = ids[n:]
+		}
+	}
+	return described, nil
+}
+
+// reportTasks logs the exit code and the stop reason of each container of the stopped tasks
+func reportTasks(ctx context.Context, svc ecsClient, tasks []ecsTypes.Task) error {
+	essentials := map[string]map[string]bool{}
+	var failed *taskFailedError
+	for _, t := range tasks {
+		taskDef := aws.ToString(t.TaskDefinitionArn)
+		if _, ok := essentials[taskDef]; !ok {
+			out, err := svc.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+				TaskDefinition: aws.String(taskDef),
+			})
+			if err != nil {
+				return err
+			}
+			essentials[taskDef] = map[string]bool{}
+			for _, cd := range out.TaskDefinition.ContainerDefinitions {
+				// containers are essential unless explicitly marked as not essential
+				essentials[taskDef][aws.ToString(cd.Name)] = cd.Essential == nil || *cd.Essential
+			}
+		}
+
+		id := runningTask{arn: aws.ToString(t.TaskArn)}.id()
+		log.Printf("task %s stopped: %s (%s)", id, aws.ToString(t.StoppedReason), t.StopCode)
+		for _, c := range t.Containers {
+			name := aws.ToString(c.Name)
+			exitCode := "-"
+			if c.ExitCode != nil {
+				exitCode = fmt.Sprint(*c.ExitCode)
+			}
+			reason := aws.ToString(c.Reason)
+			if reason == "" {
+				reason = "-"
+			}
+			log.Printf("  container %q: exit code %s, reason: %s", name, exitCode, reason)
+
+			if !essentials[taskDef][name] || (c.ExitCode != nil && *c.ExitCode == 0) {
+				continue
+			}
+			if failed == nil {
+				failed = &taskFailedError{exitCode: 1}
+				if code := aws.ToInt32(c.ExitCode); code != 0 {
+					failed.exitCode = int(code)
+				}
+			}
+			failed.msgs = append(failed.msgs, fmt.Sprintf("\ttask %s: essential container %q exited with code %s: %s",
+				id, name, exitCode, reason))
+		}
+	}
+	if failed != nil {
+		return failed
+	}
+	return nil
+}
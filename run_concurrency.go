@@ -0,0 +1,140 @@
+package ecschedule
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// concurrency policies of run when tasks of the rule are still running
+const (
+	concurrencyAllow  = "allow"
+	concurrencyForbid = "forbid"
+	concurrencyWait   = "wait"
+)
+
+func validateConcurrency(c string) error {
+	switch c {
+	case "", concurrencyAllow, concurrencyForbid, concurrencyWait:
+		return nil
+	}
+	return fmt.Errorf("invalid -concurrency %q: allow, forbid or wait expected", c)
+}
+
+// startedBy returns the startedBy of the tasks started by run, which identifies the tasks of the rule.
+// RunTask accepts only letters, numbers, hyphens, slashes and underscores in startedBy, so it is
+// "ecschedule/<rule name>" with the other characters of the name, e.g. dots, replaced with underscores.
+// A short hash of the name is appended then, so that "a.b" and "a_b" are still told apart.
+func (r *Rule) startedBy() string {
+	name := strings.Map(func(c rune) rune {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '-', c == '/', c == '_':
+			return c
+		}
+		return '_'
+	}, r.Name)
+	if name != r.Name {
+		sum := sha256.Sum256([]byte(r.Name))
+		name += "-" + hex.EncodeToString(sum[:4])
+	}
+	return "ecschedule/" + name
+}
+
+// activeTasks returns the ARNs of the tasks of the rule which are PENDING or RUNNING on the cluster
+// of the input. When the target has a group, tasks in the group are found regardless of who started
+// them, so that the executions by the schedule are detected as well. Otherwise the tasks started by
+// run are found by startedBy.
+func (r *Rule) activeTasks(ctx context.Context, svc ecsClient, in *ecs.RunTaskInput) ([]string, error) {
+	listIn := &ecs.ListTasksInput{
+		Cluster:       in.Cluster,
+		DesiredStatus: ecsTypes.DesiredStatusRunning,
+	}
+	group := aws.ToString(in.Group)
+	if group != "" {
+		// ListTasks can't filter by group, so narrow down by the family and describe them
+		listIn.Family = aws.String(taskDefinitionFamily(aws.ToString(in.TaskDefinition)))
+	} else {
+		listIn.StartedBy = aws.String(r.startedBy())
+	}
+	var arns []string
+	for {
+		out, err := svc.ListTasks(ctx, listIn)
+		if err != nil {
+			return nil, err
+		}
+		arns = append(arns, out.TaskArns...)
+		if out.NextToken == nil {
+			break
+		}
+		listIn.NextToken = out.NextToken
+	}
+	if group == "" || len(arns) == 0 {
+		return arns, nil
+	}
+	var tasks []runningTask
+	for _, arn := range arns {
+		tasks = append(tasks, runningTask{cluster: aws.ToString(in.Cluster), arn: arn})
+	}
+	described, err := describeTasks(ctx, svc, tasks)
+	if err != nil {
+		return nil, err
+	}
+	var active []string
+	for _, t := range described {
+		if aws.ToString(t.Group) == group {
+			active = append(active, aws.ToString(t.TaskArn))
+		}
+	}
+	return active, nil
+}
+
+// taskDefinitionFamily returns the family of the task definition ARN or family:revision
+func taskDefinitionFamily(taskDef string) string {
+	family := taskDef[strings.LastIndex(taskDef, "/")+1:]
+	if i := strings.LastIndex(family, ":"); i >= 0 {
+		family = family[:i]
+	}
+	return family
+}
+
+// checkConcurrency applies the concurrency policy to the inputs before the tasks are started.
+// It fails with forbid and waits for the active tasks to stop with wait.
+func (r *Rule) checkConcurrency(ctx context.Context, svc ecsClient, inputs []*ecs.RunTaskInput, policy string) error {
+	if policy == "" || policy == concurrencyAllow {
+		return nil
+	}
+	for {
+		var active []string
+		for _, in := range inputs {
+			arns, err := r.activeTasks(ctx, svc, in)
+			if err != nil {
+				return err
+			}
+			active = append(active, arns...)
+		}
+		if len(active) == 0 {
+			return nil
+		}
+		if policy == concurrencyForbid {
+			return fmt.Errorf("the rule %q has %d running task(s): %s", r.Name, len(active), strings.Join(active, ", "))
+		}
+		log.Printf("waiting for %d running task(s) of the rule %q to stop", len(active), r.Name)
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return errors.New("timed out waiting for the running tasks to stop")
+			}
+			return ctx.Err()
+		case <-time.After(taskPollInterval):
+		}
+	}
+}
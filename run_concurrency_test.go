@@ -0,0 +1,106 @@
+package ecschedule
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+func TestRule_run_concurrency(t *testing.T) {
+	defer func(d time.Duration) { taskPollInterval = d }(taskPollInterval)
+	taskPollInterval = time.Millisecond
+
+	running := []ecsTypes.Task{{
+		TaskArn:   aws.String("arn:aws:ecs:us-east-1:339:task/api/running"),
+		StartedBy: aws.String("ecschedule/hoge"),
+		Group:     aws.String("family:task1"),
+	}, {
+		TaskArn:   aws.String("arn:aws:ecs:us-east-1:339:task/api/other"),
+		StartedBy: aws.String("ecschedule/fuga"),
+		Group:     aws.String("family:other"),
+	}}
+
+	t.Run("forbid", func(t *testing.T) {
+		svc := &fakeECS{active: running, activeLists: 1 << 30}
+		_, err := testRunRule().run(context.Background(), svc, nil, &RunOptions{Concurrency: concurrencyForbid})
+		if err == nil || !strings.Contains(err.Error(), `the rule "hoge" has 1 running task(s)`) {
+			t.Errorf("running tasks should be reported, but: %v", err)
+		}
+		if len(svc.runInputs) != 0 {
+			t.Errorf("tasks should not be started")
+		}
+		if by := aws.ToString(svc.listInputs[0].StartedBy); by != "ecschedule/hoge" {
+			t.Errorf("tasks should be listed by startedBy, but: %s", by)
+		}
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		svc := &fakeECS{active: running, activeLists: 2, exitCodes: map[string]*int32{"app": aws.Int32(0)}}
+		if _, err := testRunRule().run(context.Background(), svc, nil, &RunOptions{Concurrency: concurrencyWait}); err != nil {
+			t.Fatalf("error should be nil, but: %s", err)
+		}
+		if len(svc.listInputs) != 3 || len(svc.runInputs) != 1 {
+			t.Errorf("tasks should be started after the running tasks stopped: listed %d times", len(svc.listInputs))
+		}
+		if by := aws.ToString(svc.runInputs[0].StartedBy); by != "ecschedule/hoge" {
+			t.Errorf("startedBy should be set, but: %s", by)
+		}
+	})
+
+	t.Run("group", func(t *testing.T) {
+		r := testRunRule()
+		r.Target.Group = "family:other"
+		svc := &fakeECS{active: running, activeLists: 1 << 30}
+		_, err := r.run(context.Background(), svc, nil, &RunOptions{Concurrency: concurrencyForbid})
+		if err == nil || !strings.Contains(err.Error(), "task/api/other") {
+			t.Errorf("tasks in the group should be reported, but: %v", err)
+		}
+		if in := svc.listInputs[0]; in.StartedBy != nil || aws.ToString(in.Family) != "task1" {
+			t.Errorf("tasks should be listed by family: %+v", in)
+		}
+	})
+
+	t.Run("allow", func(t *testing.T) {
+		svc := &fakeECS{active: running, activeLists: 1 << 30}
+		if _, err := testRunRule().run(context.Background(), svc, nil, &RunOptions{NoWait: true}); err != nil {
+			t.Fatalf("error should be nil, but: %s", err)
+		}
+		if len(svc.listInputs) != 0 {
+			t.Errorf("running tasks should not be checked")
+		}
+	})
+}
+
+func TestTaskDefinitionFamily(t *testing.T) {
+	for in, expect := range map[string]string{
+		"arn:aws:ecs:us-east-1:339:task-definition/batch:12": "batch",
+		"batch:3": "batch",
+		"batch":   "batch",
+	} {
+		if got := taskDefinitionFamily(in); got != expect {
+			t.Errorf("%s: got %s, expected %s", in, got, expect)
+		}
+	}
+}
+
+func TestRule_startedBy(t *testing.T) {
+	testCases := []struct {
+		name, want string
+	}{
+		{"hoge", "ecschedule/hoge"},
+		{"nightly-batch_v2", "ecschedule/nightly-batch_v2"},
+		{"ledger.reconcile", "ecschedule/ledger_reconcile-50be094d"},
+		{"daily report", "ecschedule/daily_report-48e2ac04"},
+		{"a.b", "ecschedule/a_b-2e7336dc"},
+		{"a_b", "ecschedule/a_b"},
+	}
+	for _, tc := range testCases {
+		if got := (&Rule{Name: tc.name}).startedBy(); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package ecschedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// maxRunTaskCount is the maximum number of tasks RunTask starts at once
+const maxRunTaskCount = 10
+
+// RunOverrides are ad-hoc overrides merged on top of the targets of the rule by run
+type RunOverrides struct {
+	// TaskDefinition is a family:revision, a family or an ARN of the task definition
+	TaskDefinition string
+	Count          int32
+	// TaskOverride is merged on top of the task and container overrides of the targets
+	TaskOverride *ecsTypes.TaskOverride
+}
+
+// containerEnvFlag accumulates repeated -env CONTAINER.KEY=VALUE pairs
+type containerEnvFlag struct {
+	env map[string]map[string]string
+}
+
+func newContainerEnvFlag() *containerEnvFlag {
+	return &containerEnvFlag{env: map[string]map[string]string{}}
+}
+
+func (e *containerEnvFlag) String() string {
+	var parts []string
+	for c, kv := range e.env {
+		for k, v := range kv {
+			parts = append(parts, c+"."+k+"="+v)
+		}
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+func (e *containerEnvFlag) Set(s string) error {
+	i := strings.IndexByte(s, '=')
+	if i < 0 {
+		return fmt.Errorf("invalid -env %q: CONTAINER.KEY=VALUE expected", s)
+	}
+	container, key, ok := strings.Cut(s[:i], ".")
+	if !ok || container == "" || key == "" {
+		return fmt.Errorf("invalid -env %q: CONTAINER.KEY=VALUE expected", s)
+	}
+	if e.env[container] == nil {
+		e.env[container] = map[string]string{}
+	}
+	e.env[container][key] = s[i+1:]
+	return nil
+}
+
+func (e *containerEnvFlag) taskOverride() *ecsTypes.TaskOverride {
+	to := &ecsTypes.TaskOverride{}
+	for _, c := range sortedKeys(e.env) {
+		co := ecsTypes.ContainerOverride{Name: aws.String(c)}
+		for _, k := range sortedKeys(e.env[c]) {
+			co.Environment = append(co.Environment, ecsTypes.KeyValuePair{
+				Name:  aws.String(k),
+				Value: aws.String(e.env[c][k]),
+			})
+		}
+		to.ContainerOverrides = append(to.ContainerOverrides, co)
+	}
+	return to
+}
+
+// containerCommandFlag accumulates repeated -command CONTAINER='...' values
+type containerCommandFlag struct {
+	commands map[string][]string
+}
+
+func newContainerCommandFlag() *containerCommandFlag {
+	return &containerCommandFlag{commands: map[string][]string{}}
+}
+
+func (c *containerCommandFlag) String() string {
+	var parts []string
+	for name, cmd := range c.commands {
+		parts = append(parts, name+"="+strings.Join(cmd, " "))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+func (c *containerCommandFlag) Set(s string) error {
+	name, cmd, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("invalid -command %q: CONTAINER=COMMAND expected", s)
+	}
+	args, err := splitCommand(cmd)
+	if err != nil {
+		return fmt.Errorf("invalid -command %q: %w", s, err)
+	}
+	c.commands[name] = args
+	return nil
+}
+
+func (c *containerCommandFlag) taskOverride() *ecsTypes.TaskOverride {
+	to := &ecsTypes.TaskOverride{}
+	for _, name := range sortedKeys(c.commands) {
+		to.ContainerOverrides = append(to.ContainerOverrides, ecsTypes.ContainerOverride{
+			Name:    aws.String(name),
+			Command: c.commands[name],
+		})
+	}
+	return to
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// splitCommand splits the command line into arguments like a shell does, supporting single and
+// double quotes and backslash escapes. A JSON array of strings is also accepted as is.
+func splitCommand(s string) ([]string, error) {
+	if strings.HasPrefix(strings.TrimSpace(s), "[") {
+		var args []string
+		if err := json.Unmarshal([]byte(s), &args); err != nil {
+			return nil, err
+		}
+		return args, nil
+	}
+	var (
+		args    []string
+		cur     strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+	for _, ch := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(ch)
+			escaped = false
+		case ch == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(ch)
+			}
+		case ch == '\'' || ch == '"':
+			quote, inArg = ch, true
+		case unicode.IsSpace(ch):
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(ch)
+			inArg = true
+		}
+	}
+	if escaped || quote != 0 {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	if len(args) == 0 {
+		return nil, errors.New("empty command")
+	}
+	return args, nil
+}
+
+// newRunOverrides builds the overrides of run. The task override in the overrides file, which is
+// in the same format as the overrides of RunTask, is merged first and then -env and -command.
+func newRunOverrides(taskDef string, count int, overridesFile string, env *containerEnvFlag, command *containerCommandFlag) (*RunOverrides, error) {
+	if count < 0 || count > maxRunTaskCount {
+		return nil, fmt.Errorf("-count must be between 1 and %d", maxRunTaskCount)
+	}
+	ov := &RunOverrides{
+		TaskDefinition: taskDef,
+		Count:          int32(count),
+		TaskOverride:   &ecsTypes.TaskOverride{},
+	}
+	if overridesFile != "" {
+		bs, err := os.ReadFile(overridesFile)
+		if err != nil {
+			return nil, err
+		}
+		to := &ecsTypes.TaskOverride{}
+		if err := json.Unmarshal(bs, to); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", overridesFile, err)
+		}
+		mergeTaskOverride(ov.TaskOverride, to)
+	}
+	mergeTaskOverride(ov.TaskOverride, env.taskOverride())
+	mergeTaskOverride(ov.TaskOverride, command.taskOverride())
+	return ov, nil
+}
+
+// apply merges the overrides into the input of RunTask
+func (ov *RunOverrides) apply(in *ecs.RunTaskInput, r *Rule) {
+	if ov == nil {
+		return
+	}
+	if ov.TaskDefinition != "" {
+		in.TaskDefinition = aws.String((&Target{TaskDefinition: ov.TaskDefinition}).taskDefinitionArn(r))
+	}
+	if ov.Count > 0 {
+		in.Count = aws.Int32(ov.Count)
+	}
+	if ov.TaskOverride != nil {
+		if in.Overrides == nil {
+			in.Overrides = &ecsTypes.TaskOverride{}
+		}
+		mergeTaskOverride(in.Overrides, ov.TaskOverride)
+	}
+}
+
+// mergeTaskOverride merges src into dst. Container overrides are merged by the container name:
+// environment variables are merged by the name and the other specified fields replace the ones of dst.
+func mergeTaskOverride(dst, src *ecsTypes.TaskOverride) {
+	if src.Cpu != nil {
+		dst.Cpu = src.Cpu
+	}
+	if src.Memory != nil {
+		dst.Memory = src.Memory
+	}
+	if src.TaskRoleArn != nil {
+		dst.TaskRoleArn = src.TaskRoleArn
+	}
+	if src.ExecutionRoleArn != nil {
+		dst.ExecutionRoleArn = src.ExecutionRoleArn
+	}
+	if src.EphemeralStorage != nil {
+		dst.EphemeralStorage = src.EphemeralStorage
+	}
+	if len(src.InferenceAcceleratorOverrides) > 0 {
+		dst.InferenceAcceleratorOverrides = src.InferenceAcceleratorOverrides
+	}
+	for _, sco := range src.ContainerOverrides {
+		i := containerOverrideIndex(dst.ContainerOverrides, aws.ToString(sco.Name))
+		if i < 0 {
+			dst.ContainerOverrides = append(dst.ContainerOverrides, sco)
+			continue
+		}
+		dco := &dst.ContainerOverrides[i]
+		if len(sco.Command) > 0 {
+			dco.Command = sco.Command
+		}
+		for _, kv := range sco.Environment {
+			replaced := false
+			for j := range dco.Environment {
+				if aws.ToString(dco.Environment[j].Name) == aws.ToString(kv.Name) {
+					dco.Environment[j].Value = kv.Value
+					replaced = true
+				}
+			}
+			if !replaced {
+				dco.Environment = append(dco.Environment, kv)
+			}
+		}
+		if len(sco.EnvironmentFiles) > 0 {
+			dco.EnvironmentFiles = sco.EnvironmentFiles
+		}
+		if len(sco.ResourceRequirements) > 0 {
+			dco.ResourceRequirements = sco.ResourceRequirements
+		}
+		if sco.Cpu != nil {
+			dco.Cpu = sco.Cpu
+		}
+		if sco.Memory != nil {
+			dco.Memory = sco.Memory
+		}
+		if sco.MemoryReservation != nil {
+			dco.MemoryReservation = sco.MemoryReservation
+		}
+	}
+}
+
+func containerOverrideIndex(cos []ecsTypes.ContainerOverride, name string) int {
+	for i, co := range cos {
+		if aws.ToString(co.Name) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// runTaskInputJSON renders the input of RunTask in the format of `aws ecs run-task --cli-input-json`,
+// omitting empty fields.
+func runTaskInputJSON(in *ecs.RunTaskInput) ([]byte, error) {
+	bs, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return nil, err
+	}
+	v, _ = compactJSONValue(v)
+	return json.MarshalIndent(v, "", "  ")
+}
+
+// compactJSONValue drops null, empty strings, empty arrays and empty objects and lower-cases
+// the first letter of keys. It reports whether the value is empty.
+func compactJSONValue(v interface{}) (interface{}, bool) {
+	switch vv := v.(type) {
+	case nil:
+		return nil, true
+	case string:
+		return vv, vv == ""
+	case []interface{}:
+		var arr []interface{}
+		for _, e := range vv {
+			if e, empty := compactJSONValue(e); !empty {
+				arr = append(arr, e)
+			}
+		}
+		return arr, len(arr) == 0
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for k, e := range vv {
+			if e, empty := compactJSONValue(e); !empty {
+				m[strings.ToLower(k[:1])+k[1:]] = e
+			}
+		}
+		return m, len(m) == 0
+	}
+	return v, false
+}
@@ -0,0 +1,133 @@
+package ecschedule
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestSplitCommand(t *testing.T) {
+	testCases := []struct {
+		in     string
+		expect []string
+	}{
+		{`echo hello`, []string{"echo", "hello"}},
+		{`sh -c 'echo "hello world"'`, []string{"sh", "-c", `echo "hello world"`}},
+		{`echo "a\"b" c\ d ''`, []string{"echo", `a"b`, "c d", ""}},
+		{`["echo", "hello world"]`, []string{"echo", "hello world"}},
+	}
+	for _, tc := range testCases {
+		got, err := splitCommand(tc.in)
+		if err != nil {
+			t.Errorf("%s: error should be nil, but: %s", tc.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.expect) {
+			t.Errorf("%s: got %q, expected %q", tc.in, got, tc.expect)
+		}
+	}
+	for _, in := range []string{`echo 'hello`, ``, `echo \`} {
+		if _, err := splitCommand(in); err == nil {
+			t.Errorf("%s: error should be returned", in)
+		}
+	}
+}
+
+func TestRunOverrides_apply(t *testing.T) {
+	ovFile := filepath.Join(t.TempDir(), "overrides.json")
+	if err := os.WriteFile(ovFile, []byte(`{
+  "memory": "2048",
+  "containerOverrides": [
+    {"name": "app", "environment": [{"name": "MODE", "value": "file"}, {"name": "DEBUG", "value": "1"}]},
+    {"name": "sidecar", "cpu": 128}
+  ]
+}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	env := newContainerEnvFlag()
+	command := newContainerCommandFlag()
+	for _, s := range []string{"app.MODE=rerun", "app.DATE=2024-01-01"} {
+		if err := env.Set(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := command.Set(`app=batch --date '2024-01-01'`); err != nil {
+		t.Fatal(err)
+	}
+	ov, err := newRunOverrides("task1:3", 1, ovFile, env, command)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := testRunRule()
+	r.Target.ContainerOverrides = []*ContainerOverride{{
+		Name:        "app",
+		Command:     []string{"batch"},
+		Environment: map[string]string{"MODE": "scheduled", "TZ": "UTC"},
+	}}
+	inputs, err := r.runTaskInputs(&RunOptions{Overrides: ov})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := inputs[0]
+	if aws.ToString(in.TaskDefinition) != "arn:aws:ecs:us-east-1:339:task-definition/task1:3" || aws.ToInt32(in.Count) != 1 {
+		t.Errorf("task definition and count should be overridden: %+v", in)
+	}
+	if aws.ToString(in.Overrides.Memory) != "2048" {
+		t.Errorf("task override should be merged: %+v", in.Overrides)
+	}
+	if len(in.Overrides.ContainerOverrides) != 2 {
+		t.Fatalf("container overrides should be merged by name: %+v", in.Overrides.ContainerOverrides)
+	}
+	app := in.Overrides.ContainerOverrides[0]
+	if !reflect.DeepEqual(app.Command, []string{"batch", "--date", "2024-01-01"}) {
+		t.Errorf("command should be overridden: %q", app.Command)
+	}
+	env2 := map[string]string{}
+	for _, kv := range app.Environment {
+		env2[aws.ToString(kv.Name)] = aws.ToString(kv.Value)
+	}
+	expect := map[string]string{"MODE": "rerun", "TZ": "UTC", "DEBUG": "1", "DATE": "2024-01-01"}
+	if !reflect.DeepEqual(env2, expect) {
+		t.Errorf("environment should be merged: %v", env2)
+	}
+}
+
+func TestRule_dryRun(t *testing.T) {
+	env := newContainerEnvFlag()
+	if err := env.Set("app.MODE=rerun"); err != nil {
+		t.Fatal(err)
+	}
+	ov, err := newRunOverrides("", 0, "", env, newContainerCommandFlag())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := testRunRule().dryRun(&RunOptions{Overrides: ov, Out: out}); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []string{
+		`"cluster": "arn:aws:ecs:us-east-1:339:cluster/api"`,
+		`"count": 2`,
+		`"name": "MODE"`,
+		`"value": "rerun"`,
+	} {
+		if !strings.Contains(out.String(), e) {
+			t.Errorf("output should contain %s, but:\n%s", e, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "null") {
+		t.Errorf("empty fields should be omitted:\n%s", out.String())
+	}
+}
+
+func TestNewRunOverrides_invalidCount(t *testing.T) {
+	if _, err := newRunOverrides("", 11, "", newContainerEnvFlag(), newContainerCommandFlag()); err == nil {
+		t.Error("error should be returned for -count over 10")
+	}
+}
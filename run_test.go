@@ -0,0 +1,242 @@
+package ecschedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+// fakeECS is a fake ECS client. Each task reaches STOPPED after it is described polls times.
+type fakeECS struct {
+	polls     int
+	exitCodes map[string]*int32 // by container name
+	essential map[string]bool   // by container name
+	awslogs   bool              // containers use the awslogs log driver
+	// active tasks are listed by ListTasks until it is called activeLists times
+	active      []ecsTypes.Task
+	activeLists int
+	listInputs  []*ecs.ListTasksInput
+
+	runInputs []*ecs.RunTaskInput
+	described int
+}
+
+func (f *fakeECS) RunTask(_ context.Context, in *ecs.RunTaskInput, _ ...func(*ecs.Options)) (*ecs.RunTaskOutput, error) {
+	f.runInputs = append(f.runInputs, in)
+	var tasks []ecsTypes.Task
+	for i := int32(0); i < aws.ToInt32(in.Count); i++ {
+		tasks = append(tasks, ecsTypes.Task{
+			TaskArn:           aws.String(fmt.Sprintf("arn:aws:ecs:us-east-1:339:task/%s/task%d-%d", aws.ToString(in.Cluster), len(f.runInputs), i)),
+			TaskDefinitionArn: in.TaskDefinition,
+		})
+	}
+	return &ecs.RunTaskOutput{Tasks: tasks}, nil
+}
+
+func (f *fakeECS) ListTasks(_ context.Context, in *ecs.ListTasksInput, _ ...func(*ecs.Options)) (*ecs.ListTasksOutput, error) {
+	f.listInputs = append(f.listInputs, in)
+	out := &ecs.ListTasksOutput{}
+	if len(f.listInputs) > f.activeLists {
+		return out, nil
+	}
+	for _, t := range f.active {
+		if in.StartedBy != nil && aws.ToString(t.StartedBy) != aws.ToString(in.StartedBy) {
+			continue
+		}
+		out.TaskArns = append(out.TaskArns, aws.ToString(t.TaskArn))
+	}
+	return out, nil
+}
+
+func (f *fakeECS) DescribeTasks(_ context.Context, in *ecs.DescribeTasksInput, _ ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error) {
+	var tasks []ecsTypes.Task
+	if active := f.activeTasks(in.Tasks); len(active) > 0 {
+		return &ecs.DescribeTasksOutput{Tasks: active}, nil
+	}
+	f.described++
+	for _, arn := range in.Tasks {
+		t := ecsTypes.Task{
+			TaskArn:           aws.String(arn),
+			TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:339:task-definition/task1"),
+			LastStatus:        aws.String("RUNNING"),
+		}
+		if f.described > f.polls {
+			t.LastStatus = aws.String("STOPPED")
+			t.StopCode = ecsTypes.TaskStopCodeEssentialContainerExited
+			t.StoppedReason = aws.String("Essential container in task exited")
+			for name, code := range f.exitCodes {
+				t.Containers = append(t.Containers, ecsTypes.Container{Name: aws.String(name), ExitCode: code})
+			}
+		}
+		tasks = append(tasks, t)
+	}
+	return &ecs.DescribeTasksOutput{Tasks: tasks}, nil
+}
+
+func (f *fakeECS) activeTasks(arns []string) []ecsTypes.Task {
+	var tasks []ecsTypes.Task
+	for _, t := range f.active {
+		for _, arn := range arns {
+			if aws.ToString(t.TaskArn) == arn {
+				tasks = append(tasks, t)
+			}
+		}
+	}
+	return tasks
+}
+
+func (f *fakeECS) DescribeTaskDefinition(_ context.Context, in *ecs.DescribeTaskDefinitionInput, _ ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error) {
+	var defs []ecsTypes.ContainerDefinition
+	for name, essential := range f.essential {
+		cd := ecsTypes.ContainerDefinition{Name: aws.String(name), Essential: aws.Bool(essential)}
+		if f.awslogs {
+			cd.LogConfiguration = &ecsTypes.LogConfiguration{
+				LogDriver: ecsTypes.LogDriverAwslogs,
+				Options: map[string]string{
+					"awslogs-group":         "/ecs/task1",
+					"awslogs-stream-prefix": "ecs",
+				},
+			}
+		}
+		defs = append(defs, cd)
+	}
+	return &ecs.DescribeTaskDefinitionOutput{
+		TaskDefinition: &ecsTypes.TaskDefinition{ContainerDefinitions: defs},
+	}, nil
+}
+
+func testRunRule() *Rule {
+	return &Rule{
+		Name:               "hoge",
+		ScheduleExpression: "rate(1 hour)",
+		Target: &Target{
+			TaskDefinition: "task1",
+			TaskCount:      2,
+		},
+		BaseConfig: &BaseConfig{
+			Region:    "us-east-1",
+			Cluster:   "api",
+			AccountID: "339",
+		},
+	}
+}
+
+func TestRule_run(t *testing.T) {
+	defer func(d time.Duration) { taskPollInterval = d }(taskPollInterval)
+	taskPollInterval = time.Millisecond
+
+	testCases := []struct {
+		name      string
+		exitCodes map[string]*int32
+		essential map[string]bool
+		exitCode  int
+	}{{
+		name:      "succeeded",
+		exitCodes: map[string]*int32{"app": aws.Int32(0), "sidecar": aws.Int32(137)},
+		essential: map[string]bool{"app": true, "sidecar": false},
+	}, {
+		name:      "essential container failed",
+		exitCodes: map[string]*int32{"app": aws.Int32(3)},
+		essential: map[string]bool{"app": true},
+		exitCode:  3,
+	}, {
+		name:      "essential container never started",
+		exitCodes: map[string]*int32{"app": nil},
+		essential: map[string]bool{"app": true},
+		exitCode:  1,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &fakeECS{polls: 2, exitCodes: tc.exitCodes, essential: tc.essential}
+			_, err := testRunRule().run(context.Background(), svc, nil, &RunOptions{})
+			if svc.described != 3 {
+				t.Errorf("tasks should be polled until stopped, but described %d times", svc.described)
+			}
+			if tc.exitCode == 0 {
+				if err != nil {
+					t.Errorf("error should be nil, but: %s", err)
+				}
+				return
+			}
+			var tfe *taskFailedError
+			if !errors.As(err, &tfe) {
+				t.Fatalf("taskFailedError should be returned, but: %v", err)
+			}
+			if tfe.ExitCode() != tc.exitCode {
+				t.Errorf("exit code should be %d, but: %d", tc.exitCode, tfe.ExitCode())
+			}
+			// two tasks are started
+			if len(tfe.msgs) != 2 {
+				t.Errorf("failures of all tasks should be reported, but: %v", tfe.msgs)
+			}
+		})
+	}
+}
+
+func TestRule_run_noWait(t *testing.T) {
+	svc := &fakeECS{}
+	if _, err := testRunRule().run(context.Background(), svc, nil, &RunOptions{NoWait: true}); err != nil {
+		t.Fatalf("error should be nil, but: %s", err)
+	}
+	if svc.described != 0 {
+		t.Errorf("tasks should not be described with noWait, but described %d times", svc.described)
+	}
+}
+
+func TestRule_run_timeout(t *testing.T) {
+	defer func(d time.Duration) { taskPollInterval = d }(taskPollInterval)
+	taskPollInterval = time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	svc := &fakeECS{polls: 1 << 30}
+	_, err := testRunRule().run(ctx, svc, nil, &RunOptions{})
+	if err == nil || err.Error() != "timed out waiting for the tasks to stop" {
+		t.Errorf("timeout error should be returned, but: %v", err)
+	}
+}
+
+func TestTarget_runTaskInput(t *testing.T) {
+	r := testRunRule()
+	r.Target.Group = "batch"
+	r.Target.PlatformVersion = "1.4.0"
+	r.Target.PropagateTags = aws.String("TASK_DEFINITION")
+	r.Target.CapacityProviderStrategy = []*CapacityProviderStrategyItem{
+		{CapacityProvider: "FARGATE_SPOT", Weight: 1},
+	}
+	r.Target.NetworkConfiguration = &NetworkConfiguration{
+		AwsVpcConfiguration: &AwsVpcConfiguration{
+			Subnets:        []string{"subnet-01234567"},
+			AssignPublicIP: "ENABLED",
+		},
+	}
+	in, err := r.Target.runTaskInput(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aws.ToString(in.Cluster) != "arn:aws:ecs:us-east-1:339:cluster/api" {
+		t.Errorf("cluster should be the ARN, but: %s", aws.ToString(in.Cluster))
+	}
+	if aws.ToString(in.Group) != "batch" || aws.ToString(in.PlatformVersion) != "1.4.0" || in.PropagateTags != "TASK_DEFINITION" {
+		t.Errorf("unexpected RunTask input: %+v", in)
+	}
+	if len(in.CapacityProviderStrategy) != 1 || aws.ToString(in.CapacityProviderStrategy[0].CapacityProvider) != "FARGATE_SPOT" {
+		t.Errorf("capacity provider strategy should be passed to RunTask: %+v", in.CapacityProviderStrategy)
+	}
+	if in.LaunchType != "" {
+		t.Errorf("launch type should be empty with capacity provider strategy, but: %s", in.LaunchType)
+	}
+	nc := in.NetworkConfiguration.AwsvpcConfiguration
+	if len(nc.Subnets) != 1 || nc.AssignPublicIp != "ENABLED" {
+		t.Errorf("unexpected network configuration: %+v", nc)
+	}
+	if aws.ToInt32(in.Count) != 2 || aws.ToString(in.TaskDefinition) != "arn:aws:ecs:us-east-1:339:task-definition/task1" {
+		t.Errorf("unexpected RunTask input: %+v", in)
+	}
+}
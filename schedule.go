@@ -0,0 +1,180 @@
+package ecschedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/winebarrel/cronplan"
+)
+
+// schedule expands a schedule expression into fire times
+type schedule interface {
+	// next returns the first fire time at or after from, or the zero time when it never fires again
+	next(from time.Time) time.Time
+}
+
+type cronSchedule struct {
+	exp *cronplan.Expression
+	loc *time.Location
+}
+
+func (s *cronSchedule) next(from time.Time) time.Time {
+	return s.exp.Next(from.In(s.loc))
+}
+
+// rateSchedule fires at every interval. The actual fire times depend on when the rule was created,
+// which is unknown, so they are aligned to the interval (e.g. rate(1 hour) fires on the hour).
+type rateSchedule struct {
+	interval time.Duration
+}
+
+func (s *rateSchedule) next(from time.Time) time.Time {
+	t := from.Truncate(s.interval)
+	if t.Before(from) {
+		t = t.Add(s.interval)
+	}
+	return t
+}
+
+// atSchedule fires only once (one-time schedules of the scheduler backend)
+type atSchedule struct {
+	at time.Time
+}
+
+func (s *atSchedule) next(from time.Time) time.Time {
+	if s.at.Before(from) {
+		return time.Time{}
+	}
+	return s.at
+}
+
+// windowSchedule limits the fire times to startDate and endDate of the scheduler backend
+type windowSchedule struct {
+	schedule
+	start, end *time.Time
+}
+
+func (s *windowSchedule) next(from time.Time) time.Time {
+	if s.start != nil && from.Before(*s.start) {
+		from = *s.start
+	}
+	t := s.schedule.next(from)
+	if s.end != nil && t.After(*s.end) {
+		return time.Time{}
+	}
+	return t
+}
+
+// parseSchedule parses the schedule expression. Cron and at expressions are evaluated in the
+// timezone, or in UTC when it is empty.
+func parseSchedule(expr, timezone string) (schedule, error) {
+	loc := time.UTC
+	if timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+		}
+	}
+	switch {
+	case strings.HasPrefix(expr, "rate(") && strings.HasSuffix(expr, ")"):
+		interval, err := parseRate(expr[len("rate(") : len(expr)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression %q: %w", expr, err)
+		}
+		return &rateSchedule{interval: interval}, nil
+	case strings.HasPrefix(expr, "cron(") && strings.HasSuffix(expr, ")"):
+		exp, err := cronplan.Parse(expr[len("cron(") : len(expr)-1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression %q: %w", expr, err)
+		}
+		return &cronSchedule{exp: exp, loc: loc}, nil
+	case strings.HasPrefix(expr, "at(") && strings.HasSuffix(expr, ")"):
+		at, err := time.ParseInLocation("2006-01-02T15:04:05", expr[len("at("):len(expr)-1], loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression %q: %w", expr, err)
+		}
+		return &atSchedule{at: at}, nil
+	}
+	return nil, fmt.Errorf("invalid expression: %q", expr)
+}
+
+// parseRate parses the value of rate(), e.g. "5 minutes"
+func parseRate(v string) (time.Duration, error) {
+	value, unit, ok := strings.Cut(v, " ")
+	if !ok {
+		return 0, fmt.Errorf("value and unit expected")
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("positive integer value expected: %q", value)
+	}
+	var d time.Duration
+	switch unit {
+	case "minute", "minutes":
+		d = time.Minute
+	case "hour", "hours":
+		d = time.Hour
+	case "day", "days":
+		d = 24 * time.Hour
+	default:
+		return 0, fmt.Errorf("unknown unit %q", unit)
+	}
+	return time.Duration(n) * d, nil
+}
+
+// schedule returns the schedule of the rule. It returns nil for rules triggered by events.
+func (r *Rule) schedule() (schedule, error) {
+	if r.ScheduleExpression == "" {
+		return nil, nil
+	}
+	s, err := parseSchedule(r.ScheduleExpression, r.ScheduleExpressionTimezone)
+	if err != nil {
+		return nil, err
+	}
+	if r.StartDate != nil || r.EndDate != nil {
+		s = &windowSchedule{schedule: s, start: r.StartDate, end: r.EndDate}
+	}
+	return s, nil
+}
+
+// nextFireTimes returns the next n fire times after from
+func nextFireTimes(s schedule, from time.Time, n int) []time.Time {
+	var times []time.Time
+	t := ceilMinute(from)
+	for len(times) < n {
+		t = s.next(t)
+		if t.IsZero() {
+			break
+		}
+		times = append(times, t)
+		t = t.Add(time.Minute)
+	}
+	return times
+}
+
+// fireTimesBetween returns the fire times in [from, to)
+func fireTimesBetween(s schedule, from, to time.Time) []time.Time {
+	var times []time.Time
+	t := ceilMinute(from)
+	for {
+		t = s.next(t)
+		if t.IsZero() || !t.Before(to) {
+			break
+		}
+		times = append(times, t)
+		t = t.Add(time.Minute)
+	}
+	return times
+}
+
+// ceilMinute rounds t up to the minute, since schedules fire at minute granularity
+func ceilMinute(t time.Time) time.Time {
+	m := t.Truncate(time.Minute)
+	if m.Before(t) {
+		m = m.Add(time.Minute)
+	}
+	return m
+}
@@ -0,0 +1,136 @@
+package ecschedule
+
+import (
+	"testing"
+	"time"
+)
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	tm, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tm
+}
+
+func formatTimes(times []time.Time) []string {
+	var ss []string
+	for _, t := range times {
+		ss = append(ss, t.Format(time.RFC3339))
+	}
+	return ss
+}
+
+func TestNextFireTimes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expr     string
+		timezone string
+		from     string
+		want     []string
+	}{
+		{
+			name: "cron",
+			expr: "cron(0 9 * * ? *)",
+			from: "2026-01-01T09:00:30Z",
+			want: []string{"2026-01-02T09:00:00Z", "2026-01-03T09:00:00Z", "2026-01-04T09:00:00Z"},
+		},
+		{
+			name: "cron on the minute",
+			expr: "cron(*/30 * * * ? *)",
+			from: "2026-01-01T09:00:00Z",
+			want: []string{"2026-01-01T09:00:00Z", "2026-01-01T09:30:00Z", "2026-01-01T10:00:00Z"},
+		},
+		{
+			name:     "cron in timezone",
+			expr:     "cron(0 9 * * ? *)",
+			timezone: "Asia/Tokyo",
+			from:     "2026-01-01T00:00:00Z",
+			want:     []string{"2026-01-01T09:00:00+09:00", "2026-01-02T09:00:00+09:00", "2026-01-03T09:00:00+09:00"},
+		},
+		{
+			name: "rate",
+			expr: "rate(15 minutes)",
+			from: "2026-01-01T09:07:00Z",
+			want: []string{"2026-01-01T09:15:00Z", "2026-01-01T09:30:00Z", "2026-01-01T09:45:00Z"},
+		},
+		{
+			name: "rate of a day",
+			expr: "rate(1 day)",
+			from: "2026-01-01T09:07:00Z",
+			want: []string{"2026-01-02T00:00:00Z", "2026-01-03T00:00:00Z", "2026-01-04T00:00:00Z"},
+		},
+		{
+			name: "at",
+			expr: "at(2026-01-05T10:00:00)",
+			from: "2026-01-01T00:00:00Z",
+			want: []string{"2026-01-05T10:00:00Z"},
+		},
+		{
+			name: "at in the past",
+			expr: "at(2025-01-05T10:00:00)",
+			from: "2026-01-01T00:00:00Z",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := parseSchedule(tc.expr, tc.timezone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := formatTimes(nextFireTimes(s, mustTime(t, tc.from), 3))
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("got %v, want %v", got, tc.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestParseSchedule_error(t *testing.T) {
+	for _, expr := range []string{
+		"rate(0 minutes)",
+		"rate(5 weeks)",
+		"rate(minutes)",
+		"cron(0 9 * * * *)",
+		"at(2026-01-05)",
+		"every 5 minutes",
+	} {
+		if _, err := parseSchedule(expr, ""); err == nil {
+			t.Errorf("%s: error expected", expr)
+		}
+	}
+	if _, err := parseSchedule("cron(0 9 * * ? *)", "Mars/Olympus_Mons"); err == nil {
+		t.Error("error expected for the unknown timezone")
+	}
+}
+
+func TestRuleSchedule_window(t *testing.T) {
+	start := mustTime(t, "2026-01-03T00:00:00Z")
+	end := mustTime(t, "2026-01-04T12:00:00Z")
+	r := &Rule{
+		ScheduleExpression: "cron(0 9 * * ? *)",
+		StartDate:          &start,
+		EndDate:            &end,
+	}
+	s, err := r.schedule()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := formatTimes(fireTimesBetween(s, mustTime(t, "2026-01-01T00:00:00Z"), mustTime(t, "2026-01-10T00:00:00Z")))
+	want := []string{"2026-01-03T09:00:00Z", "2026-01-04T09:00:00Z"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	s, err = (&Rule{EventPattern: map[string]interface{}{"source": []string{"aws.ecs"}}}).schedule()
+	if err != nil || s != nil {
+		t.Errorf("no schedule expected for the event pattern: %v, %v", s, err)
+	}
+}
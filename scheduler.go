@@ -0,0 +1,636 @@
+package ecschedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cweTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchevents/types"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	schedulerTypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/goccy/go-yaml"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	backendEvents    = "events"
+	backendScheduler = "scheduler"
+
+	defaultScheduleGroup = "default"
+)
+
+// FlexibleTimeWindow represents the flexible time window of EventBridge Scheduler
+type FlexibleTimeWindow struct {
+	Mode                   string `yaml:"mode" json:"mode"` // OFF | FLEXIBLE
+	MaximumWindowInMinutes *int32 `yaml:"maximumWindowInMinutes,omitempty" json:"maximumWindowInMinutes,omitempty"`
+}
+
+func (ftw *FlexibleTimeWindow) isOff() bool {
+	return ftw == nil || ftw.Mode == string(schedulerTypes.FlexibleTimeWindowModeOff)
+}
+
+func (r *Rule) useScheduler() bool {
+	return r.BaseConfig != nil && r.Backend == backendScheduler
+}
+
+func (r *Rule) scheduleGroup() string {
+	if r.ScheduleGroup == "" {
+		return defaultScheduleGroup
+	}
+	return r.ScheduleGroup
+}
+
+func (r *Rule) validateBackend() error {
+	var backend string
+	if r.BaseConfig != nil {
+		backend = r.Backend
+	}
+	switch backend {
+	case "", backendEvents:
+		if r.ScheduleExpressionTimezone != "" || r.FlexibleTimeWindow != nil || r.StartDate != nil || r.EndDate != nil {
+			return errors.New("scheduleExpressionTimezone, flexibleTimeWindow, startDate and endDate are only available with the scheduler backend")
+		}
+		return nil
+	case backendScheduler:
+	default:
+		return fmt.Errorf("unknown backend %q", backend)
+	}
+
+	if r.EventPattern != nil {
+		return errors.New("eventPattern is not available with the scheduler backend")
+	}
+	targets := r.targets()
+	if len(targets) != 1 {
+		return errors.New("the scheduler backend supports only a single target")
+	}
+	if targets[0].TargetID != "" {
+		return errors.New("targetId is not available with the scheduler backend")
+	}
+	if ta := targets[0]; ta.TargetCluster != "" && ta.targetARN(r) != (&Target{}).targetARN(r) {
+		// schedules on the other clusters are not tracked by getSchedule
+		return errors.New("targetCluster other than the cluster is not available with the scheduler backend")
+	}
+	if tz := r.ScheduleExpressionTimezone; tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return fmt.Errorf("invalid scheduleExpressionTimezone: %w", err)
+		}
+	}
+	if ftw := r.FlexibleTimeWindow; ftw != nil {
+		switch schedulerTypes.FlexibleTimeWindowMode(ftw.Mode) {
+		case schedulerTypes.FlexibleTimeWindowModeOff:
+			if ftw.MaximumWindowInMinutes != nil {
+				return errors.New("maximumWindowInMinutes is not available when flexibleTimeWindow mode is OFF")
+			}
+		case schedulerTypes.FlexibleTimeWindowModeFlexible:
+			if ftw.MaximumWindowInMinutes == nil {
+				return errors.New("maximumWindowInMinutes is required when flexibleTimeWindow mode is FLEXIBLE")
+			}
+		default:
+			return fmt.Errorf("invalid flexibleTimeWindow mode %q", ftw.Mode)
+		}
+	}
+	if r.StartDate != nil && r.EndDate != nil && !r.StartDate.Before(*r.EndDate) {
+		return errors.New("startDate must be before endDate")
+	}
+	return nil
+}
+
+// normalizeSchedule normalizes the fields of the scheduler backend which have
+// several representations of the same value, so that diffs stay stable.
+func (r *Rule) normalizeSchedule() {
+	if r.ScheduleExpressionTimezone == "UTC" {
+		r.ScheduleExpressionTimezone = ""
+	}
+	if r.FlexibleTimeWindow.isOff() {
+		r.FlexibleTimeWindow = nil
+	}
+	if r.StartDate != nil {
+		t := r.StartDate.UTC()
+		r.StartDate = &t
+	}
+	if r.EndDate != nil {
+		t := r.EndDate.UTC()
+		r.EndDate = &t
+	}
+}
+
+func (r *Rule) flexibleTimeWindow() *schedulerTypes.FlexibleTimeWindow {
+	if r.FlexibleTimeWindow.isOff() {
+		return &schedulerTypes.FlexibleTimeWindow{
+			Mode: schedulerTypes.FlexibleTimeWindowModeOff,
+		}
+	}
+	return &schedulerTypes.FlexibleTimeWindow{
+		Mode:                   schedulerTypes.FlexibleTimeWindowMode(r.FlexibleTimeWindow.Mode),
+		MaximumWindowInMinutes: r.FlexibleTimeWindow.MaximumWindowInMinutes,
+	}
+}
+
+func (r *Rule) scheduleExpressionTimezone() *string {
+	if r.ScheduleExpressionTimezone == "" {
+		return nil
+	}
+	return aws.String(r.ScheduleExpressionTimezone)
+}
+
+func (r *Rule) scheduleState() schedulerTypes.ScheduleState {
+	if r.Disabled {
+		return schedulerTypes.ScheduleStateDisabled
+	}
+	return schedulerTypes.ScheduleStateEnabled
+}
+
+// scheduleTarget builds the target of the schedule from the same parameters as PutTargetsInput
+func (r *Rule) scheduleTarget() *schedulerTypes.Target {
+	return schedulerTarget(r.targets()[0].target(r))
+}
+
+// CreateScheduleInput creates schedule input
+func (r *Rule) CreateScheduleInput() *scheduler.CreateScheduleInput {
+	return &scheduler.CreateScheduleInput{
+		Name:                       aws.String(r.Name),
+		GroupName:                  aws.String(r.scheduleGroup()),
+		Description:                aws.String(r.Description),
+		ScheduleExpression:         aws.String(r.ScheduleExpression),
+		ScheduleExpressionTimezone: r.scheduleExpressionTimezone(),
+		FlexibleTimeWindow:         r.flexibleTimeWindow(),
+		StartDate:                  r.StartDate,
+		EndDate:                    r.EndDate,
+		State:                      r.scheduleState(),
+		Target:                     r.scheduleTarget(),
+	}
+}
+
+// UpdateScheduleInput updates schedule input
+func (r *Rule) UpdateScheduleInput() *scheduler.UpdateScheduleInput {
+	return &scheduler.UpdateScheduleInput{
+		Name:                       aws.String(r.Name),
+		GroupName:                  aws.String(r.scheduleGroup()),
+		Description:                aws.String(r.Description),
+		ScheduleExpression:         aws.String(r.ScheduleExpression),
+		ScheduleExpressionTimezone: r.scheduleExpressionTimezone(),
+		FlexibleTimeWindow:         r.flexibleTimeWindow(),
+		StartDate:                  r.StartDate,
+		EndDate:                    r.EndDate,
+		State:                      r.scheduleState(),
+		Target:                     r.scheduleTarget(),
+	}
+}
+
+// schedulerTarget converts the target of CloudWatch Events into the one of EventBridge Scheduler
+func schedulerTarget(t *cweTypes.Target) *schedulerTypes.Target {
+	st := &schedulerTypes.Target{
+		Arn:     t.Arn,
+		RoleArn: t.RoleArn,
+		Input:   t.Input,
+	}
+	if dlc := t.DeadLetterConfig; dlc != nil {
+		st.DeadLetterConfig = &schedulerTypes.DeadLetterConfig{Arn: dlc.Arn}
+	}
+	if rp := t.RetryPolicy; rp != nil {
+		st.RetryPolicy = &schedulerTypes.RetryPolicy{
+			MaximumEventAgeInSeconds: rp.MaximumEventAgeInSeconds,
+			MaximumRetryAttempts:     rp.MaximumRetryAttempts,
+		}
+	}
+	p := t.EcsParameters
+	if p == nil {
+		return st
+	}
+	sp := &schedulerTypes.EcsParameters{
+		TaskDefinitionArn: p.TaskDefinitionArn,
+		TaskCount:         p.TaskCount,
+		Group:             p.Group,
+		LaunchType:        schedulerTypes.LaunchType(p.LaunchType),
+		PlatformVersion:   p.PlatformVersion,
+		PropagateTags:     schedulerTypes.PropagateTags(p.PropagateTags),
+		ReferenceId:       p.ReferenceId,
+	}
+	if p.EnableECSManagedTags {
+		sp.EnableECSManagedTags = aws.Bool(true)
+	}
+	if p.EnableExecuteCommand {
+		sp.EnableExecuteCommand = aws.Bool(true)
+	}
+	for _, cps := range p.CapacityProviderStrategy {
+		sp.CapacityProviderStrategy = append(sp.CapacityProviderStrategy, schedulerTypes.CapacityProviderStrategyItem{
+			CapacityProvider: cps.CapacityProvider,
+			Base:             cps.Base,
+			Weight:           cps.Weight,
+		})
+	}
+	if nc := p.NetworkConfiguration; nc != nil && nc.AwsvpcConfiguration != nil {
+		sp.NetworkConfiguration = &schedulerTypes.NetworkConfiguration{
+			AwsvpcConfiguration: &schedulerTypes.AwsVpcConfiguration{
+				Subnets:        nc.AwsvpcConfiguration.Subnets,
+				SecurityGroups: nc.AwsvpcConfiguration.SecurityGroups,
+				AssignPublicIp: schedulerTypes.AssignPublicIp(nc.AwsvpcConfiguration.AssignPublicIp),
+			},
+		}
+	}
+	for _, pc := range p.PlacementConstraints {
+		sp.PlacementConstraints = append(sp.PlacementConstraints, schedulerTypes.PlacementConstraint{
+			Expression: pc.Expression,
+			Type:       schedulerTypes.PlacementConstraintType(pc.Type),
+		})
+	}
+	for _, ps := range p.PlacementStrategy {
+		sp.PlacementStrategy = append(sp.PlacementStrategy, schedulerTypes.PlacementStrategy{
+			Field: ps.Field,
+			Type:  schedulerTypes.PlacementStrategyType(ps.Type),
+		})
+	}
+	for _, tag := range p.Tags {
+		sp.Tags = append(sp.Tags, map[string]string{aws.ToString(tag.Key): aws.ToString(tag.Value)})
+	}
+	st.EcsParameters = sp
+	return st
+}
+
+// cweTarget converts the target of EventBridge Scheduler into the one of CloudWatch Events,
+// so that ruleGetter can read it back in the same way as the targets of rules.
+func cweTarget(id string, st *schedulerTypes.Target) *cweTypes.Target {
+	t := &cweTypes.Target{
+		Id:      aws.String(id),
+		Arn:     st.Arn,
+		RoleArn: st.RoleArn,
+		Input:   st.Input,
+	}
+	if dlc := st.DeadLetterConfig; dlc != nil {
+		t.DeadLetterConfig = &cweTypes.DeadLetterConfig{Arn: dlc.Arn}
+	}
+	if rp := st.RetryPolicy; rp != nil {
+		t.RetryPolicy = &cweTypes.RetryPolicy{
+			MaximumEventAgeInSeconds: rp.MaximumEventAgeInSeconds,
+			MaximumRetryAttempts:     rp.MaximumRetryAttempts,
+		}
+	}
+	sp := st.EcsParameters
+	if sp == nil {
+		return t
+	}
+	p := &cweTypes.EcsParameters{
+		TaskDefinitionArn:    sp.TaskDefinitionArn,
+		TaskCount:            sp.TaskCount,
+		Group:                sp.Group,
+		LaunchType:           cweTypes.LaunchType(sp.LaunchType),
+		PlatformVersion:      sp.PlatformVersion,
+		PropagateTags:        cweTypes.PropagateTags(sp.PropagateTags),
+		ReferenceId:          sp.ReferenceId,
+		EnableECSManagedTags: aws.ToBool(sp.EnableECSManagedTags),
+		EnableExecuteCommand: aws.ToBool(sp.EnableExecuteCommand),
+	}
+	if p.TaskCount == nil {
+		p.TaskCount = aws.Int32(1)
+	}
+	for _, cps := range sp.CapacityProviderStrategy {
+		p.CapacityProviderStrategy = append(p.CapacityProviderStrategy, cweTypes.CapacityProviderStrategyItem{
+			CapacityProvider: cps.CapacityProvider,
+			Base:             cps.Base,
+			Weight:           cps.Weight,
+		})
+	}
+	if nc := sp.NetworkConfiguration; nc != nil && nc.AwsvpcConfiguration != nil {
+		p.NetworkConfiguration = &cweTypes.NetworkConfiguration{
+			AwsvpcConfiguration: &cweTypes.AwsVpcConfiguration{
+				Subnets:        nc.AwsvpcConfiguration.Subnets,
+				SecurityGroups: nc.AwsvpcConfiguration.SecurityGroups,
+				AssignPublicIp: cweTypes.AssignPublicIp(nc.AwsvpcConfiguration.AssignPublicIp),
+			},
+		}
+	}
+	for _, pc := range sp.PlacementConstraints {
+		p.PlacementConstraints = append(p.PlacementConstraints, cweTypes.PlacementConstraint{
+			Expression: pc.Expression,
+			Type:       cweTypes.PlacementConstraintType(pc.Type),
+		})
+	}
+	for _, ps := range sp.PlacementStrategy {
+		p.PlacementStrategy = append(p.PlacementStrategy, cweTypes.PlacementStrategy{
+			Field: ps.Field,
+			Type:  cweTypes.PlacementStrategyType(ps.Type),
+		})
+	}
+	for _, m := range sp.Tags {
+		for k, v := range m {
+			p.Tags = append(p.Tags, cweTypes.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+	}
+	t.EcsParameters = p
+	return t
+}
+
+// getSchedule converts a remote schedule into Rule. It returns nil for the schedule which
+// does not run an ECS task on the cluster.
+func (rg *ruleGetter) getSchedule(s *scheduler.GetScheduleOutput) (*Rule, error) {
+	if s.Target == nil {
+		return nil, nil
+	}
+	name := aws.ToString(s.Name)
+	target, err := rg.getTarget(name, cweTarget(name, s.Target))
+	if err != nil {
+		return nil, err
+	}
+	if target == nil || target.TargetCluster != "" {
+		return nil, nil
+	}
+	ru := &Rule{
+		Name:                       name,
+		Description:                aws.ToString(s.Description),
+		ScheduleExpression:         aws.ToString(s.ScheduleExpression),
+		Disabled:                   s.State == schedulerTypes.ScheduleStateDisabled,
+		ScheduleExpressionTimezone: aws.ToString(s.ScheduleExpressionTimezone),
+		StartDate:                  s.StartDate,
+		EndDate:                    s.EndDate,
+	}
+	if ftw := s.FlexibleTimeWindow; ftw != nil {
+		ru.FlexibleTimeWindow = &FlexibleTimeWindow{
+			Mode:                   string(ftw.Mode),
+			MaximumWindowInMinutes: ftw.MaximumWindowInMinutes,
+		}
+	}
+	ru.normalizeSchedule()
+	ru.setTargets([]*Target{target})
+	return ru, nil
+}
+
+func newScheduleGetter(bc *BaseConfig) *ruleGetter {
+	return &ruleGetter{
+		clusterArn:       fmt.Sprintf("arn:aws:ecs:%s:%s:cluster/%s", bc.Region, bc.AccountID, bc.Cluster),
+		taskDefArnPrefix: fmt.Sprintf("arn:aws:ecs:%s:%s:task-definition/", bc.Region, bc.AccountID),
+		roleArnPrefix:    fmt.Sprintf("arn:aws:iam::%s:role/", bc.AccountID),
+		sqsArnPrefix:     fmt.Sprintf("arn:aws:sqs:%s:%s:", bc.Region, bc.AccountID),
+	}
+}
+
+// remoteSchedule returns the remote schedule of the rule. It returns nil when the schedule does not exist.
+func (r *Rule) remoteSchedule(ctx context.Context, svc *scheduler.Client) (*scheduler.GetScheduleOutput, error) {
+	s, err := svc.GetSchedule(ctx, &scheduler.GetScheduleInput{
+		Name:      aws.String(r.Name),
+		GroupName: aws.String(r.scheduleGroup()),
+	})
+	if err != nil {
+		var nfe *schedulerTypes.ResourceNotFoundException
+		if errors.As(err, &nfe) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *Rule) remoteScheduleYAML(s *scheduler.GetScheduleOutput) (string, error) {
+	if s == nil {
+		return "", nil
+	}
+	ru, err := newScheduleGetter(r.BaseConfig).getSchedule(s)
+	if err != nil || ru == nil {
+		return "", err
+	}
+	bs, err := yaml.Marshal(ru)
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
+
+func (r *Rule) scheduleDiff(ctx context.Context, svc *scheduler.Client) (from, to string, err error) {
+	s, err := r.remoteSchedule(ctx, svc)
+	if err != nil {
+		return "", "", err
+	}
+	from, err = r.remoteScheduleYAML(s)
+	if err != nil {
+		return "", "", err
+	}
+	to, err = r.localYAMLForDiff()
+	if err != nil {
+		return "", "", err
+	}
+	return from, to, nil
+}
+
+func (r *Rule) applySchedule(ctx context.Context, awsConf aws.Config, dryRun bool, format diffFormat) error {
+	svc := scheduler.NewFromConfig(awsConf, func(o *scheduler.Options) {
+		o.Region = r.Region
+	})
+	s, err := r.remoteSchedule(ctx, svc)
+	if err != nil {
+		return err
+	}
+	from, err := r.remoteScheduleYAML(s)
+	if err != nil {
+		return err
+	}
+	to, err := r.localYAMLForDiff()
+	if err != nil {
+		return err
+	}
+	if from == to {
+		log.Println("💡 skip applying. no differences")
+		return nil
+	}
+
+	var dryRunSuffix string
+	if dryRun {
+		dryRunSuffix = " (dry-run)"
+	}
+
+	diffOutput := formatDiff(r.Name, from, to, format, explainOptions{})
+	log.Printf("💡 applying following changes%s\n%s", dryRunSuffix, diffOutput)
+
+	if dryRun {
+		return nil
+	}
+	if err := r.ensureScheduleGroup(ctx, svc); err != nil {
+		return err
+	}
+	if s == nil {
+		_, err = svc.CreateSchedule(ctx, r.CreateScheduleInput())
+	} else {
+		_, err = svc.UpdateSchedule(ctx, r.UpdateScheduleInput())
+	}
+	return err
+}
+
+// ensureScheduleGroup creates the schedule group if it does not exist and tags it with
+// the tracking ID. Schedules themselves cannot be tagged, so prune relies on the tag of
+// the group. The default group cannot be tagged and is left as it is.
+func (r *Rule) ensureScheduleGroup(ctx context.Context, svc *scheduler.Client) error {
+	group := r.scheduleGroup()
+	if group == defaultScheduleGroup {
+		return nil
+	}
+	tags := []schedulerTypes.Tag{
+		{
+			Key:   aws.String(trackingIDTagKey),
+			Value: aws.String(r.TrackingID),
+		},
+	}
+	out, err := svc.GetScheduleGroup(ctx, &scheduler.GetScheduleGroupInput{
+		Name: aws.String(group),
+	})
+	if err != nil {
+		var nfe *schedulerTypes.ResourceNotFoundException
+		if !errors.As(err, &nfe) {
+			return err
+		}
+		log.Printf("creating the schedule group %q", group)
+		_, err := svc.CreateScheduleGroup(ctx, &scheduler.CreateScheduleGroupInput{
+			Name: aws.String(group),
+			Tags: tags,
+		})
+		return err
+	}
+	_, err = svc.TagResource(ctx, &scheduler.TagResourceInput{
+		ResourceArn: out.Arn,
+		Tags:        tags,
+	})
+	return err
+}
+
+func (r *Rule) deleteSchedule(ctx context.Context, awsConf aws.Config, dryRun bool, format diffFormat) error {
+	svc := scheduler.NewFromConfig(awsConf, func(o *scheduler.Options) {
+		o.Region = r.Region
+	})
+	remoteRuleYaml, err := yaml.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	var dryRunSuffix string
+	if dryRun {
+		dryRunSuffix = " (dry-run)"
+	}
+
+	diffOutput := formatDiff(r.Name, string(remoteRuleYaml), "", format, explainOptions{})
+	log.Printf("🪓 deleting following schedule%s\n%s", dryRunSuffix, diffOutput)
+
+	if dryRun {
+		return nil
+	}
+	_, err = svc.DeleteSchedule(ctx, &scheduler.DeleteScheduleInput{
+		Name:      aws.String(r.Name),
+		GroupName: aws.String(r.scheduleGroup()),
+	})
+	return err
+}
+
+// newRuleFromRemoteSchedule creates a new rule from remote (EventBridge Scheduler schedule)
+func newRuleFromRemoteSchedule(ctx context.Context, svc *scheduler.Client, bc *BaseConfig, group, name string) (*Rule, error) {
+	s, err := svc.GetSchedule(ctx, &scheduler.GetScheduleInput{
+		Name:      aws.String(name),
+		GroupName: aws.String(group),
+	})
+	if err != nil {
+		return nil, err
+	}
+	ru, err := newScheduleGetter(bc).getSchedule(s)
+	if err != nil || ru == nil {
+		return nil, err
+	}
+	base := *bc
+	base.Backend = backendScheduler
+	base.ScheduleGroup = group
+	ru.BaseConfig = &base
+	return ru, nil
+}
+
+// listScheduleNames lists names of all schedules in the schedule group
+func listScheduleNames(ctx context.Context, svc *scheduler.Client, group string) ([]string, error) {
+	var (
+		names     []string
+		nextToken *string
+	)
+	for {
+		out, err := svc.ListSchedules(ctx, &scheduler.ListSchedulesInput{
+			GroupName: aws.String(group),
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range out.Schedules {
+			names = append(names, aws.ToString(s.Name))
+		}
+		if out.NextToken == nil {
+			break
+		}
+		nextToken = out.NextToken
+	}
+	return names, nil
+}
+
+// listTrackedScheduleGroups lists schedule groups tagged with the tracking ID
+func listTrackedScheduleGroups(ctx context.Context, svc *scheduler.Client, trackingID string) ([]string, error) {
+	var (
+		groups    []string
+		nextToken *string
+	)
+	for {
+		out, err := svc.ListScheduleGroups(ctx, &scheduler.ListScheduleGroupsInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range out.ScheduleGroups {
+			if aws.ToString(g.Name) == defaultScheduleGroup {
+				continue
+			}
+			tags, err := svc.ListTagsForResource(ctx, &scheduler.ListTagsForResourceInput{
+				ResourceArn: g.Arn,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, tag := range tags.Tags {
+				if aws.ToString(tag.Key) == trackingIDTagKey && aws.ToString(tag.Value) == trackingID {
+					groups = append(groups, aws.ToString(g.Name))
+					break
+				}
+			}
+		}
+		if out.NextToken == nil {
+			break
+		}
+		nextToken = out.NextToken
+	}
+	return groups, nil
+}
+
+// Extract the schedules in the schedule groups associated with trackingId and extract those
+// that are not included in scheduleNames, which are the groups and the names joined with "/".
+func extractOrphanedSchedules(ctx context.Context, awsConf aws.Config, base *BaseConfig, scheduleNames []string) ([]*Rule, error) {
+	svc := scheduler.NewFromConfig(awsConf, func(o *scheduler.Options) {
+		o.Region = base.Region
+	})
+	groups, err := listTrackedScheduleGroups(ctx, svc, base.TrackingID)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphanedRules []*Rule
+	for _, group := range groups {
+		names, err := listScheduleNames(ctx, svc, group)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if slices.Contains(scheduleNames, group+"/"+name) {
+				continue
+			}
+			orphanedRule, err := newRuleFromRemoteSchedule(ctx, svc, base, group, name)
+			if err != nil {
+				return nil, err
+			}
+			if orphanedRule != nil {
+				orphanedRules = append(orphanedRules, orphanedRule)
+			}
+		}
+	}
+	return orphanedRules, nil
+}
@@ -0,0 +1,226 @@
+package ecschedule
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	schedulerTypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	"github.com/goccy/go-yaml"
+)
+
+func TestLoadConfig_scheduler(t *testing.T) {
+	path := "testdata/sample7.yaml"
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	c, err := LoadConfig(context.Background(), f, "339", path)
+	if err != nil {
+		t.Fatalf("error should be nil, but: %s", err)
+	}
+
+	ru := c.GetRuleByName("hoge-task-name")
+	if !ru.useScheduler() {
+		t.Fatalf("rule should use the scheduler backend")
+	}
+	in := ru.CreateScheduleInput()
+	if g, e := aws.ToString(in.GroupName), "api"; g != e {
+		t.Errorf("group should be %q, but: %q", e, g)
+	}
+	if g, e := aws.ToString(in.ScheduleExpressionTimezone), "Asia/Tokyo"; g != e {
+		t.Errorf("timezone should be %q, but: %q", e, g)
+	}
+	if g, e := in.FlexibleTimeWindow.Mode, schedulerTypes.FlexibleTimeWindowModeFlexible; g != e {
+		t.Errorf("flexible time window mode should be %q, but: %q", e, g)
+	}
+	if g, e := aws.ToString(in.Target.RoleArn), "arn:aws:iam::339:role/ecsSchedulerRole"; g != e {
+		t.Errorf("role should be %q, but: %q", e, g)
+	}
+	if g, e := aws.ToString(in.Target.EcsParameters.TaskDefinitionArn), "arn:aws:ecs:us-east-1:339:task-definition/task1"; g != e {
+		t.Errorf("task definition should be %q, but: %q", e, g)
+	}
+	if g, e := *in.StartDate, time.Date(2025, 12, 31, 15, 0, 0, 0, time.UTC); !g.Equal(e) {
+		t.Errorf("start date should be %s, but: %s", e, g)
+	}
+}
+
+func TestRule_validateBackend(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+	scheduler := &BaseConfig{Backend: backendScheduler}
+	testCases := []struct {
+		name   string
+		rule   *Rule
+		expect string
+	}{{
+		name: "events backend",
+		rule: &Rule{Target: &Target{TaskDefinition: "task1"}, BaseConfig: &BaseConfig{}},
+	}, {
+		name:   "scheduler only field with events backend",
+		rule:   &Rule{ScheduleExpressionTimezone: "Asia/Tokyo", Target: &Target{TaskDefinition: "task1"}, BaseConfig: &BaseConfig{}},
+		expect: "scheduleExpressionTimezone, flexibleTimeWindow, startDate and endDate are only available with the scheduler backend",
+	}, {
+		name:   "unknown backend",
+		rule:   &Rule{Target: &Target{TaskDefinition: "task1"}, BaseConfig: &BaseConfig{Backend: "cron"}},
+		expect: `unknown backend "cron"`,
+	}, {
+		name: "scheduler backend",
+		rule: &Rule{
+			ScheduleExpressionTimezone: "Asia/Tokyo",
+			FlexibleTimeWindow:         &FlexibleTimeWindow{Mode: "FLEXIBLE", MaximumWindowInMinutes: aws.Int32(10)},
+			StartDate:                  &end,
+			EndDate:                    &start,
+			Target:                     &Target{TaskDefinition: "task1"},
+			BaseConfig:                 scheduler,
+		},
+	}, {
+		name: "multiple targets",
+		rule: &Rule{Targets: []*Target{
+			{TaskDefinition: "task1"},
+			{TargetID: "second", TaskDefinition: "task2"},
+		}, BaseConfig: scheduler},
+		expect: "the scheduler backend supports only a single target",
+	}, {
+		name:   "target id",
+		rule:   &Rule{Target: &Target{TargetID: "hoge", TaskDefinition: "task1"}, BaseConfig: scheduler},
+		expect: "targetId is not available with the scheduler backend",
+	}, {
+		name:   "target cluster",
+		rule:   &Rule{Target: &Target{TargetCluster: "batch", TaskDefinition: "task1"}, BaseConfig: scheduler},
+		expect: "targetCluster other than the cluster is not available with the scheduler backend",
+	}, {
+		name: "target cluster of the cluster",
+		rule: &Rule{Target: &Target{TargetCluster: "api", TaskDefinition: "task1"}, BaseConfig: &BaseConfig{Backend: backendScheduler, Cluster: "api"}},
+	}, {
+		name:   "invalid timezone",
+		rule:   &Rule{ScheduleExpressionTimezone: "Mars/Olympus", Target: &Target{TaskDefinition: "task1"}, BaseConfig: scheduler},
+		expect: "invalid scheduleExpressionTimezone: unknown time zone Mars/Olympus",
+	}, {
+		name: "flexible without window",
+		rule: &Rule{
+			FlexibleTimeWindow: &FlexibleTimeWindow{Mode: "FLEXIBLE"},
+			Target:             &Target{TaskDefinition: "task1"},
+			BaseConfig:         scheduler,
+		},
+		expect: "maximumWindowInMinutes is required when flexibleTimeWindow mode is FLEXIBLE",
+	}, {
+		name: "start after end",
+		rule: &Rule{
+			StartDate:  &start,
+			EndDate:    &end,
+			Target:     &Target{TaskDefinition: "task1"},
+			BaseConfig: scheduler,
+		},
+		expect: "startDate must be before endDate",
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.validateBackend()
+			if tc.expect == "" {
+				if err != nil {
+					t.Errorf("error should be nil, but: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("error should be occurred, but nil")
+			}
+			if g := err.Error(); g != tc.expect {
+				t.Errorf("error should be %q, but: %q", tc.expect, g)
+			}
+		})
+	}
+}
+
+func TestConfig_scheduleNames(t *testing.T) {
+	c := &Config{Rules: []*Rule{
+		{Name: "hoge", BaseConfig: &BaseConfig{Backend: backendScheduler, ScheduleGroup: "api"}},
+		{Name: "fuga", BaseConfig: &BaseConfig{Backend: backendScheduler, ScheduleGroup: "batch"}},
+		{Name: "piyo", BaseConfig: &BaseConfig{}},
+	}}
+	got := c.scheduleNames([]string{"hoge", "fuga", "piyo", "missing"})
+	want := []string{"api/hoge", "batch/fuga", "default/piyo"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scheduleNames() = %v, want %v", got, want)
+	}
+}
+
+func TestSchedulerTarget_RoundTrip(t *testing.T) {
+	ru := &Rule{
+		Name:               "hoge-task-name",
+		ScheduleExpression: "cron(0 0 * * ? *)",
+		Target: &Target{
+			TaskDefinition: "task1",
+			TaskCount:      2,
+			Group:          "xxx",
+			LaunchType:     "FARGATE",
+			NetworkConfiguration: &NetworkConfiguration{
+				AwsVpcConfiguration: &AwsVpcConfiguration{
+					Subnets:        []string{"subnet-01234567"},
+					SecurityGroups: []string{"sg-11111111"},
+					AssignPublicIP: "ENABLED",
+				},
+			},
+			ContainerOverrides: []*ContainerOverride{
+				{Name: "container1", Command: []string{"subcmd"}},
+			},
+			DeadLetterConfig: &DeadLetterConfig{Sqs: "queue1"},
+			PropagateTags:    aws.String("TASK_DEFINITION"),
+			Role:             "ecsSchedulerRole",
+		},
+		BaseConfig: &BaseConfig{
+			Region:    "us-east-1",
+			Cluster:   "api",
+			AccountID: "339",
+			Backend:   backendScheduler,
+		},
+	}
+	want := ru.targets()[0].target(ru)
+	got := cweTarget(*want.Id, schedulerTarget(want))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected target\nwant: %+v\ngot:  %+v", want, got)
+	}
+
+	remote, err := newScheduleGetter(ru.BaseConfig).getTarget(ru.Name, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// compare in YAML as the diff does, since empty slices and maps are omitted there
+	g, err := yaml.Marshal(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := yaml.Marshal(ru.Target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(g) != string(e) {
+		t.Errorf("unexpected remote target\nwant:\n%s\ngot:\n%s", e, g)
+	}
+}
+
+func TestRule_normalizeSchedule(t *testing.T) {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+	ru := &Rule{
+		ScheduleExpressionTimezone: "UTC",
+		FlexibleTimeWindow:         &FlexibleTimeWindow{Mode: "OFF"},
+		StartDate:                  &start,
+	}
+	ru.normalizeSchedule()
+	if ru.ScheduleExpressionTimezone != "" {
+		t.Errorf("UTC timezone should be dropped, but: %q", ru.ScheduleExpressionTimezone)
+	}
+	if ru.FlexibleTimeWindow != nil {
+		t.Errorf("OFF flexible time window should be dropped, but: %+v", ru.FlexibleTimeWindow)
+	}
+	if g, e := *ru.StartDate, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC); g != e {
+		t.Errorf("start date should be %s, but: %s", e, g)
+	}
+}
@@ -0,0 +1,271 @@
+package ecschedule
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+)
+
+// expectedDuration returns ExpectedDuration of the rule, or zero when it is not specified
+func (r *Rule) expectedDuration() (time.Duration, error) {
+	if r.ExpectedDuration == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(r.ExpectedDuration)
+	if err != nil {
+		return 0, fmt.Errorf("invalid expectedDuration: %w", err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid expectedDuration: %q is negative", r.ExpectedDuration)
+	}
+	return d, nil
+}
+
+// taskSize is the CPU units and the memory in MiB
+type taskSize struct {
+	cpu, memory int
+}
+
+// taskSizer resolves the size of the tasks of the targets. The task definitions are described
+// only when the task override does not specify both of the CPU and the memory, with the client of
+// the region of the rule.
+type taskSizer struct {
+	newClient func(region string) ecsClient
+	clients   map[string]ecsClient
+	taskDefs  map[string]taskSize
+}
+
+func newTaskSizer(newClient func(region string) ecsClient) *taskSizer {
+	return &taskSizer{newClient: newClient, clients: map[string]ecsClient{}, taskDefs: map[string]taskSize{}}
+}
+
+// client returns the ECS client of the region, which is created once per region
+func (ts *taskSizer) client(region string) ecsClient {
+	svc, ok := ts.clients[region]
+	if !ok {
+		svc = ts.newClient(region)
+		ts.clients[region] = svc
+	}
+	return svc
+}
+
+func (ts *taskSizer) targetSize(ctx context.Context, r *Rule, ta *Target) (taskSize, error) {
+	var (
+		size         taskSize
+		cpuOk, memOk bool
+		err          error
+	)
+	if to := ta.TaskOverride; to != nil {
+		if to.Cpu != nil {
+			if size.cpu, err = strconv.Atoi(*to.Cpu); err != nil {
+				return size, fmt.Errorf("invalid cpu of the task override: %q", *to.Cpu)
+			}
+			cpuOk = true
+		}
+		if to.Memory != nil {
+			if size.memory, err = strconv.Atoi(*to.Memory); err != nil {
+				return size, fmt.Errorf("invalid memory of the task override: %q", *to.Memory)
+			}
+			memOk = true
+		}
+	}
+	if !cpuOk || !memOk {
+		tdSize, err := ts.taskDefinitionSize(ctx, r.Region, ta.taskDefinitionArn(r))
+		if err != nil {
+			return size, err
+		}
+		if !cpuOk {
+			size.cpu = tdSize.cpu
+		}
+		if !memOk {
+			size.memory = tdSize.memory
+		}
+	}
+	return size, nil
+}
+
+// taskDefinitionSize returns the task size of the task definition, or the sum of the sizes of
+// the containers when the task size is not specified
+func (ts *taskSizer) taskDefinitionSize(ctx context.Context, region, arn string) (taskSize, error) {
+	if size, ok := ts.taskDefs[arn]; ok {
+		return size, nil
+	}
+	out, err := ts.client(region).DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(arn),
+	})
+	if err != nil {
+		return taskSize{}, err
+	}
+	var size taskSize
+	td := out.TaskDefinition
+	for _, cd := range td.ContainerDefinitions {
+		size.cpu += int(cd.Cpu)
+		if cd.Memory != nil {
+			size.memory += int(*cd.Memory)
+		} else if cd.MemoryReservation != nil {
+			size.memory += int(*cd.MemoryReservation)
+		}
+	}
+	if td.Cpu != nil {
+		if size.cpu, err = strconv.Atoi(*td.Cpu); err != nil {
+			return taskSize{}, fmt.Errorf("invalid cpu of the task definition %s: %q", arn, *td.Cpu)
+		}
+	}
+	if td.Memory != nil {
+		if size.memory, err = strconv.Atoi(*td.Memory); err != nil {
+			return taskSize{}, fmt.Errorf("invalid memory of the task definition %s: %q", arn, *td.Memory)
+		}
+	}
+	ts.taskDefs[arn] = size
+	return size, nil
+}
+
+// simulatedRule is a rule to simulate, with the total size of the tasks of an execution
+type simulatedRule struct {
+	name     string
+	schedule schedule
+	duration time.Duration
+	tasks    int
+	size     taskSize
+}
+
+// newSimulatedRule returns nil for the rules which never fire by schedule
+func newSimulatedRule(ctx context.Context, r *Rule, ts *taskSizer) (*simulatedRule, error) {
+	if r.Disabled {
+		return nil, nil
+	}
+	s, err := r.schedule()
+	if err != nil || s == nil {
+		return nil, err
+	}
+	d, err := r.expectedDuration()
+	if err != nil {
+		return nil, err
+	}
+	sr := &simulatedRule{name: r.Name, schedule: s, duration: d}
+	for _, ta := range r.targets() {
+		size, err := ts.targetSize(ctx, r, ta)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+		count := int(ta.taskCount())
+		sr.tasks += count
+		sr.size.cpu += size.cpu * count
+		sr.size.memory += size.memory * count
+	}
+	return sr, nil
+}
+
+type execution struct {
+	Rule   string    `json:"rule"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Tasks  int       `json:"tasks"`
+	CPU    int       `json:"cpu"`
+	Memory int       `json:"memory"`
+}
+
+type peak struct {
+	Value int       `json:"value"`
+	At    time.Time `json:"at"`
+}
+
+// collision is the rules firing in the same minute
+type collision struct {
+	Time  time.Time `json:"time"`
+	Rules []string  `json:"rules"`
+}
+
+type simulation struct {
+	From       time.Time    `json:"from"`
+	To         time.Time    `json:"to"`
+	Executions []*execution `json:"executions"`
+	PeakTasks  peak         `json:"peakTasks"`
+	PeakCPU    peak         `json:"peakCpu"`
+	PeakMemory peak         `json:"peakMemory"`
+	Collisions []*collision `json:"collisions"`
+}
+
+// minExecutionDuration is assumed for the rules without expectedDuration, as the tasks run
+// for a while at least and schedules are at minute granularity
+const minExecutionDuration = time.Minute
+
+// simulate expands the schedules of the rules in [from, to) and finds the peaks and the collisions
+func simulate(rules []*simulatedRule, from, to time.Time) *simulation {
+	sim := &simulation{From: from.UTC(), To: to.UTC()}
+	byMinute := map[time.Time][]string{}
+	for _, sr := range rules {
+		d := sr.duration
+		if d < minExecutionDuration {
+			d = minExecutionDuration
+		}
+		for _, t := range fireTimesBetween(sr.schedule, from, to) {
+			t = t.UTC()
+			sim.Executions = append(sim.Executions, &execution{
+				Rule:   sr.name,
+				Start:  t,
+				End:    t.Add(d),
+				Tasks:  sr.tasks,
+				CPU:    sr.size.cpu,
+				Memory: sr.size.memory,
+			})
+			byMinute[t] = append(byMinute[t], sr.name)
+		}
+	}
+	sort.SliceStable(sim.Executions, func(i, j int) bool {
+		return sim.Executions[i].Start.Before(sim.Executions[j].Start)
+	})
+
+	type event struct {
+		at                 time.Time
+		tasks, cpu, memory int
+	}
+	var events []event
+	for _, e := range sim.Executions {
+		events = append(events,
+			event{at: e.Start, tasks: e.Tasks, cpu: e.CPU, memory: e.Memory},
+			event{at: e.End, tasks: -e.Tasks, cpu: -e.CPU, memory: -e.Memory})
+	}
+	// executions are half-open, so the ones ending at a time end before the ones starting at it
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].at.Equal(events[j].at) {
+			return events[i].at.Before(events[j].at)
+		}
+		return events[i].tasks < events[j].tasks
+	})
+	var tasks, cpu, memory int
+	for _, ev := range events {
+		tasks += ev.tasks
+		cpu += ev.cpu
+		memory += ev.memory
+		if tasks > sim.PeakTasks.Value {
+			sim.PeakTasks = peak{Value: tasks, At: ev.at}
+		}
+		if cpu > sim.PeakCPU.Value {
+			sim.PeakCPU = peak{Value: cpu, At: ev.at}
+		}
+		if memory > sim.PeakMemory.Value {
+			sim.PeakMemory = peak{Value: memory, At: ev.at}
+		}
+	}
+
+	for t, names := range byMinute {
+		if len(names) > 1 {
+			sim.Collisions = append(sim.Collisions, &collision{Time: t, Rules: names})
+		}
+	}
+	// the largest pile-ups first
+	sort.Slice(sim.Collisions, func(i, j int) bool {
+		ci, cj := sim.Collisions[i], sim.Collisions[j]
+		if len(ci.Rules) != len(cj.Rules) {
+			return len(ci.Rules) > len(cj.Rules)
+		}
+		return ci.Time.Before(cj.Time)
+	})
+	return sim
+}
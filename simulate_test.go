@@ -0,0 +1,158 @@
+package ecschedule
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+type fakeTaskDefs struct {
+	*fakeECS
+	defs      map[string]*ecsTypes.TaskDefinition // by ARN
+	describes int
+}
+
+func (f *fakeTaskDefs) DescribeTaskDefinition(_ context.Context, in *ecs.DescribeTaskDefinitionInput, _ ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error) {
+	f.describes++
+	return &ecs.DescribeTaskDefinitionOutput{TaskDefinition: f.defs[aws.ToString(in.TaskDefinition)]}, nil
+}
+
+func TestNewSimulatedRule(t *testing.T) {
+	svc := &fakeTaskDefs{
+		defs: map[string]*ecsTypes.TaskDefinition{
+			"arn:aws:ecs:us-east-1:334:task-definition/task1": {Cpu: aws.String("512"), Memory: aws.String("1024")},
+			"arn:aws:ecs:us-east-1:334:task-definition/task2": {
+				ContainerDefinitions: []ecsTypes.ContainerDefinition{
+					{Cpu: 256, Memory: aws.Int32(512)},
+					{Cpu: 128, MemoryReservation: aws.Int32(256)},
+				},
+			},
+		},
+	}
+	bc := &BaseConfig{Region: "us-east-1", Cluster: "api", AccountID: "334"}
+	r := &Rule{
+		Name:               "hoge",
+		ScheduleExpression: "cron(0 0 * * ? *)",
+		ExpectedDuration:   "15m",
+		Target:             &Target{TaskDefinition: "task1", TaskCount: 2},
+		Targets: []*Target{
+			{TargetID: "t2", TaskDefinition: "task2"},
+			{TargetID: "t3", TaskDefinition: "task1", TaskOverride: &TaskOverride{Memory: aws.String("4096")}},
+		},
+		BaseConfig: bc,
+	}
+	sr, err := newSimulatedRule(context.Background(), r, newTaskSizer(func(string) ecsClient { return svc }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sr.tasks != 4 {
+		t.Errorf("tasks: got %d, want 4", sr.tasks)
+	}
+	// task1 x2 + task2 (sum of the containers) + task1 with memory overridden
+	if want := (taskSize{cpu: 512*2 + 384 + 512, memory: 1024*2 + 768 + 4096}); sr.size != want {
+		t.Errorf("size: got %+v, want %+v", sr.size, want)
+	}
+	if sr.duration != 15*time.Minute {
+		t.Errorf("duration: got %s", sr.duration)
+	}
+	if svc.describes != 2 {
+		t.Errorf("task definitions should be described once each: %d", svc.describes)
+	}
+
+	r.Disabled = true
+	if sr, err := newSimulatedRule(context.Background(), r, newTaskSizer(func(string) ecsClient { return svc })); err != nil || sr != nil {
+		t.Errorf("disabled rules should be ignored: %v, %v", sr, err)
+	}
+}
+
+func TestTaskSizer_region(t *testing.T) {
+	// the task definitions are described in the region of the rule
+	svcs := map[string]*fakeTaskDefs{
+		"us-east-1": {defs: map[string]*ecsTypes.TaskDefinition{
+			"arn:aws:ecs:us-east-1:334:task-definition/task1": {Cpu: aws.String("512"), Memory: aws.String("1024")},
+		}},
+		"ap-northeast-1": {defs: map[string]*ecsTypes.TaskDefinition{
+			"arn:aws:ecs:ap-northeast-1:334:task-definition/task1": {Cpu: aws.String("256"), Memory: aws.String("512")},
+		}},
+	}
+	var created []string
+	ts := newTaskSizer(func(region string) ecsClient {
+		created = append(created, region)
+		return svcs[region]
+	})
+	for _, region := range []string{"us-east-1", "ap-northeast-1", "us-east-1"} {
+		r := &Rule{
+			Name:       "hoge",
+			Target:     &Target{TaskDefinition: "task1"},
+			BaseConfig: &BaseConfig{Region: region, Cluster: "api", AccountID: "334"},
+		}
+		size, err := ts.targetSize(context.Background(), r, r.Target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := taskSize{cpu: 512, memory: 1024}
+		if region == "ap-northeast-1" {
+			want = taskSize{cpu: 256, memory: 512}
+		}
+		if size != want {
+			t.Errorf("%s: got %+v, want %+v", region, size, want)
+		}
+	}
+	if len(created) != 2 {
+		t.Errorf("a client should be created once per region: %v", created)
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	mustSchedule := func(expr string) schedule {
+		s, err := parseSchedule(expr, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	rules := []*simulatedRule{
+		{name: "daily", schedule: mustSchedule("cron(0 0 * * ? *)"), duration: 30 * time.Minute, tasks: 2, size: taskSize{cpu: 1024, memory: 2048}},
+		{name: "hourly", schedule: mustSchedule("rate(1 hour)"), duration: 20 * time.Minute, tasks: 1, size: taskSize{cpu: 256, memory: 512}},
+		{name: "quarter", schedule: mustSchedule("cron(15 * * * ? *)"), tasks: 1, size: taskSize{cpu: 512, memory: 1024}},
+	}
+	from := mustTime(t, "2026-01-01T00:00:00Z")
+	sim := simulate(rules, from, from.Add(3*time.Hour))
+
+	if got := len(sim.Executions); got != 1+3+3 {
+		t.Errorf("executions: got %d, want 7", got)
+	}
+	// daily, hourly and quarter overlap at 00:15
+	if sim.PeakTasks.Value != 4 || !sim.PeakTasks.At.Equal(mustTime(t, "2026-01-01T00:00:00Z").Add(15*time.Minute)) {
+		t.Errorf("peak tasks: got %+v", sim.PeakTasks)
+	}
+	if sim.PeakCPU.Value != 1024+256+512 {
+		t.Errorf("peak cpu: got %+v", sim.PeakCPU)
+	}
+	if sim.PeakMemory.Value != 2048+512+1024 {
+		t.Errorf("peak memory: got %+v", sim.PeakMemory)
+	}
+	if len(sim.Collisions) != 1 {
+		t.Fatalf("collisions: got %d, want 1", len(sim.Collisions))
+	}
+	if co := sim.Collisions[0]; !co.Time.Equal(from) || strings.Join(co.Rules, ",") != "daily,hourly" {
+		t.Errorf("collision: got %+v", co)
+	}
+
+	out := formatSimulation(sim, len(rules), 10, false)
+	for _, s := range []string{
+		"7 execution(s) of 3 rule(s)",
+		"peak tasks:  4 at 2026-01-01T00:15:00Z",
+		"peak vCPU:   1.75 at 2026-01-01T00:15:00Z",
+		"2026-01-01T00:00:00Z  2 rules: daily, hourly",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("%q is not in the output:\n%s", s, out)
+		}
+	}
+}
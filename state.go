@@ -0,0 +1,318 @@
+package ecschedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchevents"
+	cweTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchevents/types"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	schedulerTypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+)
+
+// keys of the tags recording who disabled the rule with the disable or pause subcommand, why and until when
+const (
+	disabledByTagKey     = "ecschedule:disabled-by"
+	disabledAtTagKey     = "ecschedule:disabled-at"
+	disabledReasonTagKey = "ecschedule:disabled-reason"
+	pausedUntilTagKey    = "ecschedule:paused-until"
+)
+
+// maxTagValueLen is the maximum length of the tag values of EventBridge
+const maxTagValueLen = 256
+
+// ruleStateClient is the subset of the CloudWatch Events API to change the state of rules.
+// It is replaced with a fake in tests.
+type ruleStateClient interface {
+	EnableRule(context.Context, *cloudwatchevents.EnableRuleInput, ...func(*cloudwatchevents.Options)) (*cloudwatchevents.EnableRuleOutput, error)
+	DisableRule(context.Context, *cloudwatchevents.DisableRuleInput, ...func(*cloudwatchevents.Options)) (*cloudwatchevents.DisableRuleOutput, error)
+	DescribeRule(context.Context, *cloudwatchevents.DescribeRuleInput, ...func(*cloudwatchevents.Options)) (*cloudwatchevents.DescribeRuleOutput, error)
+	TagResource(context.Context, *cloudwatchevents.TagResourceInput, ...func(*cloudwatchevents.Options)) (*cloudwatchevents.TagResourceOutput, error)
+	UntagResource(context.Context, *cloudwatchevents.UntagResourceInput, ...func(*cloudwatchevents.Options)) (*cloudwatchevents.UntagResourceOutput, error)
+	ListTagsForResource(context.Context, *cloudwatchevents.ListTagsForResourceInput, ...func(*cloudwatchevents.Options)) (*cloudwatchevents.ListTagsForResourceOutput, error)
+}
+
+// stateChange records the rule disabled out of band by the disable or pause subcommand.
+// until is the time to resume the paused rule in RFC 3339.
+type stateChange struct {
+	by, at, reason, until string
+}
+
+func (sc *stateChange) String() string {
+	s := fmt.Sprintf("disabled by %s at %s", sc.by, sc.at)
+	if sc.until != "" {
+		s = fmt.Sprintf("paused until %s by %s at %s", sc.until, sc.by, sc.at)
+	}
+	if sc.reason != "" {
+		s += fmt.Sprintf(" (reason: %s)", sc.reason)
+	}
+	return s
+}
+
+func (sc *stateChange) tags() []cweTypes.Tag {
+	tags := []cweTypes.Tag{
+		{Key: aws.String(disabledByTagKey), Value: aws.String(truncateTagValue(sc.by))},
+		{Key: aws.String(disabledAtTagKey), Value: aws.String(sc.at)},
+	}
+	if sc.reason != "" {
+		tags = append(tags, cweTypes.Tag{Key: aws.String(disabledReasonTagKey), Value: aws.String(truncateTagValue(sc.reason))})
+	}
+	if sc.until != "" {
+		tags = append(tags, cweTypes.Tag{Key: aws.String(pausedUntilTagKey), Value: aws.String(sc.until)})
+	}
+	return tags
+}
+
+// due reports whether the pause has expired at now
+func (sc *stateChange) due(now time.Time) bool {
+	if sc.until == "" {
+		return false
+	}
+	until, err := time.Parse(time.RFC3339, sc.until)
+	// resume the rule with a broken tag rather than leaving it paused forever
+	return err != nil || !now.Before(until)
+}
+
+func truncateTagValue(v string) string {
+	if r := []rune(v); len(r) > maxTagValueLen {
+		return string(r[:maxTagValueLen])
+	}
+	return v
+}
+
+// SetState enables or disables the rule without changing the other parameters. Disabling records
+// who disabled the rule and why in the tags of the rule, so that diff and apply can tell it was
+// disabled out of band. Schedules of the scheduler backend can't be tagged, so they can only be
+// enabled; apply would enable a schedule disabled without the record again.
+func (r *Rule) SetState(ctx context.Context, awsConf aws.Config, enabled bool, by, reason string, dryRun bool) error {
+	if r.useScheduler() {
+		if !enabled {
+			return fmt.Errorf("rule %q: disable is not available with the scheduler backend because schedules can't be tagged", r.Name)
+		}
+		svc := scheduler.NewFromConfig(awsConf, func(o *scheduler.Options) {
+			o.Region = r.Region
+		})
+		return r.enableSchedule(ctx, svc, dryRun)
+	}
+	svc := cloudwatchevents.NewFromConfig(awsConf, func(o *cloudwatchevents.Options) {
+		o.Region = r.Region
+	})
+	return r.setRuleState(ctx, svc, enabled, &stateChange{
+		by:     by,
+		at:     time.Now().UTC().Format(time.RFC3339),
+		reason: reason,
+	}, dryRun)
+}
+
+func (r *Rule) setRuleState(ctx context.Context, svc ruleStateClient, enabled bool, sc *stateChange, dryRun bool) error {
+	var dryRunSuffix string
+	if dryRun {
+		dryRunSuffix = " (dry-run)"
+	}
+	if enabled {
+		log.Printf("enabling the rule %q%s", r.Name, dryRunSuffix)
+		if dryRun {
+			return nil
+		}
+		if _, err := svc.EnableRule(ctx, &cloudwatchevents.EnableRuleInput{
+			Name:         aws.String(r.Name),
+			EventBusName: r.eventBusName(),
+		}); err != nil {
+			return err
+		}
+		_, err := svc.UntagResource(ctx, &cloudwatchevents.UntagResourceInput{
+			ResourceARN: aws.String(r.ruleARN()),
+			TagKeys:     []string{disabledByTagKey, disabledAtTagKey, disabledReasonTagKey, pausedUntilTagKey},
+		})
+		return err
+	}
+
+	log.Printf("disabling the rule %q%s: %s", r.Name, dryRunSuffix, sc)
+	if dryRun {
+		return nil
+	}
+	// record the state change before disabling the rule, so that apply never finds the rule
+	// disabled without the record and enables it again. the tags are ignored while the rule is
+	// enabled, so they do no harm when disabling fails.
+	if _, err := svc.UntagResource(ctx, &cloudwatchevents.UntagResourceInput{
+		ResourceARN: aws.String(r.ruleARN()),
+		TagKeys:     []string{disabledReasonTagKey, pausedUntilTagKey},
+	}); err != nil {
+		return fmt.Errorf("failed to record the state change in the tags of the rule %q, so it is left as it is: %w", r.Name, err)
+	}
+	if _, err := svc.TagResource(ctx, &cloudwatchevents.TagResourceInput{
+		ResourceARN: aws.String(r.ruleARN()),
+		Tags:        sc.tags(),
+	}); err != nil {
+		return fmt.Errorf("failed to record the state change in the tags of the rule %q, so it is left as it is: %w", r.Name, err)
+	}
+	_, err := svc.DisableRule(ctx, &cloudwatchevents.DisableRuleInput{
+		Name:         aws.String(r.Name),
+		EventBusName: r.eventBusName(),
+	})
+	return err
+}
+
+// enableSchedule enables the remote schedule without changing the other parameters
+func (r *Rule) enableSchedule(ctx context.Context, svc *scheduler.Client, dryRun bool) error {
+	s, err := r.remoteSchedule(ctx, svc)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return fmt.Errorf("schedule %q not found in the schedule group %q", r.Name, r.scheduleGroup())
+	}
+	var dryRunSuffix string
+	if dryRun {
+		dryRunSuffix = " (dry-run)"
+	}
+	log.Printf("enabling the schedule %q%s", r.Name, dryRunSuffix)
+	if dryRun {
+		return nil
+	}
+	_, err = svc.UpdateSchedule(ctx, &scheduler.UpdateScheduleInput{
+		Name:                       s.Name,
+		GroupName:                  s.GroupName,
+		Description:                s.Description,
+		ScheduleExpression:         s.ScheduleExpression,
+		ScheduleExpressionTimezone: s.ScheduleExpressionTimezone,
+		FlexibleTimeWindow:         s.FlexibleTimeWindow,
+		StartDate:                  s.StartDate,
+		EndDate:                    s.EndDate,
+		KmsKeyArn:                  s.KmsKeyArn,
+		Target:                     s.Target,
+		ActionAfterCompletion:      s.ActionAfterCompletion,
+		State:                      schedulerTypes.ScheduleStateEnabled,
+	})
+	return err
+}
+
+// outOfBandDisabled returns the record of the disable or pause subcommand when the remote rule is
+// disabled by it. It returns nil when the rule is enabled, missing or disabled otherwise.
+func (r *Rule) outOfBandDisabled(ctx context.Context, svc ruleStateClient) (*stateChange, error) {
+	out, err := svc.DescribeRule(ctx, &cloudwatchevents.DescribeRuleInput{
+		Name:         aws.String(r.Name),
+		EventBusName: r.eventBusName(),
+	})
+	if err != nil {
+		var nfe *cweTypes.ResourceNotFoundException
+		if errors.As(err, &nfe) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if out.State != cweTypes.RuleStateDisabled {
+		return nil, nil
+	}
+	tags, err := svc.ListTagsForResource(ctx, &cloudwatchevents.ListTagsForResourceInput{
+		ResourceARN: aws.String(r.ruleARN()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var (
+		sc    = &stateChange{}
+		found bool
+	)
+	for _, tag := range tags.Tags {
+		switch aws.ToString(tag.Key) {
+		case disabledByTagKey:
+			sc.by, found = aws.ToString(tag.Value), true
+		case disabledAtTagKey:
+			sc.at = aws.ToString(tag.Value)
+		case disabledReasonTagKey:
+			sc.reason = aws.ToString(tag.Value)
+		case pausedUntilTagKey:
+			sc.until = aws.ToString(tag.Value)
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+	return sc, nil
+}
+
+// keepOutOfBandState keeps the rule disabled when it was disabled out of band by the disable or
+// pause subcommand, instead of silently enabling it again, and warns about it. It returns the
+// record of the state change if any.
+func (r *Rule) keepOutOfBandState(ctx context.Context, svc ruleStateClient) (*stateChange, error) {
+	if r.Disabled {
+		return nil, nil
+	}
+	sc, err := r.outOfBandDisabled(ctx, svc)
+	if err != nil || sc == nil {
+		return nil, err
+	}
+	enable := fmt.Sprintf("run `ecschedule enable -rule %s` to enable it", r.Name)
+	if sc.until != "" {
+		enable = "run `ecschedule resume-due` to resume it after the pause"
+	}
+	log.Printf("⚠️  the rule %q was %s out of band. keeping it disabled. %s", r.Name, sc, enable)
+	r.Disabled = true
+	return sc, nil
+}
+
+// annotatePause marks the disabled state in the rule YAML as paused, so that the diff shows it
+func annotatePause(ruleYAML string, sc *stateChange) string {
+	if sc == nil || sc.until == "" {
+		return ruleYAML
+	}
+	return strings.Replace(ruleYAML, "\ndisabled: true\n", fmt.Sprintf("\ndisabled: true # paused until %s\n", sc.until), 1)
+}
+
+// Pause disables the rule until the time and records the time in the tag of the rule.
+// The rule is enabled again by ResumeIfDue after the time.
+func (r *Rule) Pause(ctx context.Context, awsConf aws.Config, until time.Time, by, reason string, dryRun bool) error {
+	if r.useScheduler() {
+		return fmt.Errorf("rule %q: pause is not available with the scheduler backend because schedules can't be tagged", r.Name)
+	}
+	svc := cloudwatchevents.NewFromConfig(awsConf, func(o *cloudwatchevents.Options) {
+		o.Region = r.Region
+	})
+	return r.setRuleState(ctx, svc, false, &stateChange{
+		by:     by,
+		at:     time.Now().UTC().Format(time.RFC3339),
+		reason: reason,
+		until:  until.UTC().Format(time.RFC3339),
+	}, dryRun)
+}
+
+// ResumeIfDue enables the rule paused by Pause when the pause has expired.
+// It reports whether the rule is resumed.
+func (r *Rule) ResumeIfDue(ctx context.Context, awsConf aws.Config, now time.Time, dryRun bool) (bool, error) {
+	if r.useScheduler() {
+		return false, nil
+	}
+	svc := cloudwatchevents.NewFromConfig(awsConf, func(o *cloudwatchevents.Options) {
+		o.Region = r.Region
+	})
+	return r.resumeIfDue(ctx, svc, now, dryRun)
+}
+
+func (r *Rule) resumeIfDue(ctx context.Context, svc ruleStateClient, now time.Time, dryRun bool) (bool, error) {
+	sc, err := r.outOfBandDisabled(ctx, svc)
+	if err != nil || sc == nil || !sc.due(now) {
+		return false, err
+	}
+	log.Printf("the pause of the rule %q has expired: %s", r.Name, sc)
+	if r.Disabled {
+		// the configuration disables the rule meanwhile. drop the pause and leave it disabled
+		if dryRun {
+			return false, nil
+		}
+		_, err := svc.UntagResource(ctx, &cloudwatchevents.UntagResourceInput{
+			ResourceARN: aws.String(r.ruleARN()),
+			TagKeys:     []string{disabledByTagKey, disabledAtTagKey, disabledReasonTagKey, pausedUntilTagKey},
+		})
+		return false, err
+	}
+	if err := r.setRuleState(ctx, svc, true, nil, dryRun); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,184 @@
+package ecschedule
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchevents"
+	cweTypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchevents/types"
+)
+
+// fakeRuleState is a fake CloudWatch Events client holding the state and the tags of a rule
+type fakeRuleState struct {
+	state  cweTypes.RuleState
+	tags   map[string]string
+	tagErr error
+}
+
+func (f *fakeRuleState) EnableRule(_ context.Context, _ *cloudwatchevents.EnableRuleInput, _ ...func(*cloudwatchevents.Options)) (*cloudwatchevents.EnableRuleOutput, error) {
+	f.state = cweTypes.RuleStateEnabled
+	return &cloudwatchevents.EnableRuleOutput{}, nil
+}
+
+func (f *fakeRuleState) DisableRule(_ context.Context, _ *cloudwatchevents.DisableRuleInput, _ ...func(*cloudwatchevents.Options)) (*cloudwatchevents.DisableRuleOutput, error) {
+	f.state = cweTypes.RuleStateDisabled
+	return &cloudwatchevents.DisableRuleOutput{}, nil
+}
+
+func (f *fakeRuleState) DescribeRule(_ context.Context, in *cloudwatchevents.DescribeRuleInput, _ ...func(*cloudwatchevents.Options)) (*cloudwatchevents.DescribeRuleOutput, error) {
+	return &cloudwatchevents.DescribeRuleOutput{Name: in.Name, State: f.state}, nil
+}
+
+func (f *fakeRuleState) TagResource(_ context.Context, in *cloudwatchevents.TagResourceInput, _ ...func(*cloudwatchevents.Options)) (*cloudwatchevents.TagResourceOutput, error) {
+	if f.tagErr != nil {
+		return nil, f.tagErr
+	}
+	for _, tag := range in.Tags {
+		f.tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return &cloudwatchevents.TagResourceOutput{}, nil
+}
+
+func (f *fakeRuleState) UntagResource(_ context.Context, in *cloudwatchevents.UntagResourceInput, _ ...func(*cloudwatchevents.Options)) (*cloudwatchevents.UntagResourceOutput, error) {
+	for _, k := range in.TagKeys {
+		delete(f.tags, k)
+	}
+	return &cloudwatchevents.UntagResourceOutput{}, nil
+}
+
+func (f *fakeRuleState) ListTagsForResource(_ context.Context, _ *cloudwatchevents.ListTagsForResourceInput, _ ...func(*cloudwatchevents.Options)) (*cloudwatchevents.ListTagsForResourceOutput, error) {
+	var tags []cweTypes.Tag
+	for k, v := range f.tags {
+		tags = append(tags, cweTypes.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return &cloudwatchevents.ListTagsForResourceOutput{Tags: tags}, nil
+}
+
+func TestRule_setRuleState(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeRuleState{
+		state: cweTypes.RuleStateEnabled,
+		tags:  map[string]string{trackingIDTagKey: "app"},
+	}
+	r := testRunRule()
+	sc := &stateChange{by: "arn:aws:sts::339:assumed-role/oncall/alice", at: "2024-01-01T00:00:00Z", reason: "INC-123"}
+	if err := r.setRuleState(ctx, svc, false, sc, false); err != nil {
+		t.Fatal(err)
+	}
+	if svc.state != cweTypes.RuleStateDisabled || svc.tags[disabledReasonTagKey] != "INC-123" {
+		t.Errorf("the rule should be disabled with the reason: %s %v", svc.state, svc.tags)
+	}
+
+	got, err := r.outOfBandDisabled(ctx, svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || *got != *sc {
+		t.Errorf("the state change should be read from the tags, but: %v", got)
+	}
+
+	// apply keeps the rule disabled instead of enabling it again
+	if _, err := r.keepOutOfBandState(ctx, svc); err != nil {
+		t.Fatal(err)
+	}
+	if !r.Disabled {
+		t.Errorf("the rule should be kept disabled")
+	}
+
+	if err := r.setRuleState(ctx, svc, true, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if svc.state != cweTypes.RuleStateEnabled || len(svc.tags) != 1 {
+		t.Errorf("the rule should be enabled and the tags should be removed: %s %v", svc.state, svc.tags)
+	}
+	if got, _ := r.outOfBandDisabled(ctx, svc); got != nil {
+		t.Errorf("enabled rule should not be reported, but: %v", got)
+	}
+}
+
+func TestRule_setRuleState_tagError(t *testing.T) {
+	// the rule is left enabled when the state change can't be recorded
+	svc := &fakeRuleState{
+		state:  cweTypes.RuleStateEnabled,
+		tags:   map[string]string{},
+		tagErr: errors.New("AccessDeniedException"),
+	}
+	r := testRunRule()
+	sc := &stateChange{by: "arn:aws:sts::339:assumed-role/oncall/alice", at: "2024-01-01T00:00:00Z"}
+	if err := r.setRuleState(context.Background(), svc, false, sc, false); err == nil {
+		t.Fatal("error should be occurred, but nil")
+	}
+	if svc.state != cweTypes.RuleStateEnabled {
+		t.Errorf("the rule should be left enabled, but: %s", svc.state)
+	}
+}
+
+func TestRule_SetState_scheduler(t *testing.T) {
+	r := &Rule{Name: "hoge", BaseConfig: &BaseConfig{Backend: backendScheduler}}
+	err := r.SetState(context.Background(), aws.Config{}, false, "arn:aws:sts::339:assumed-role/oncall/alice", "", true)
+	if err == nil || !strings.Contains(err.Error(), "disable is not available with the scheduler backend") {
+		t.Errorf("disable should be refused with the scheduler backend, but: %v", err)
+	}
+}
+
+func TestRule_outOfBandDisabled_byConfig(t *testing.T) {
+	// rules disabled by apply have no tags of the disable subcommand
+	svc := &fakeRuleState{state: cweTypes.RuleStateDisabled, tags: map[string]string{}}
+	r := testRunRule()
+	if _, err := r.keepOutOfBandState(context.Background(), svc); err != nil {
+		t.Fatal(err)
+	}
+	if r.Disabled {
+		t.Errorf("the rule disabled by the configuration should follow the configuration")
+	}
+}
+
+func TestRule_resumeIfDue(t *testing.T) {
+	ctx := context.Background()
+	svc := &fakeRuleState{state: cweTypes.RuleStateEnabled, tags: map[string]string{}}
+	r := testRunRule()
+	sc := &stateChange{by: "alice", at: "2026-12-20T00:00:00Z", until: "2026-12-27T00:00:00Z"}
+	if err := r.setRuleState(ctx, svc, false, sc, false); err != nil {
+		t.Fatal(err)
+	}
+	if svc.tags[pausedUntilTagKey] != "2026-12-27T00:00:00Z" {
+		t.Errorf("the resume time should be tagged: %v", svc.tags)
+	}
+
+	// apply keeps the rule paused and shows it in the diff
+	got, err := r.keepOutOfBandState(ctx, svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ruleYAML := annotatePause("name: hoge\ndisabled: true\ntaskDefinition: task1\n", got)
+	if !strings.Contains(ruleYAML, "\ndisabled: true # paused until 2026-12-27T00:00:00Z\n") {
+		t.Errorf("the pause should be shown in the diff:\n%s", ruleYAML)
+	}
+
+	r = testRunRule()
+	ok, err := r.resumeIfDue(ctx, svc, time.Date(2026, 12, 26, 23, 59, 0, 0, time.UTC), false)
+	if err != nil || ok {
+		t.Errorf("the rule should not be resumed before the time: %v, %v", ok, err)
+	}
+	ok, err = r.resumeIfDue(ctx, svc, time.Date(2026, 12, 27, 0, 0, 0, 0, time.UTC), false)
+	if err != nil || !ok {
+		t.Errorf("the rule should be resumed after the time: %v, %v", ok, err)
+	}
+	if svc.state != cweTypes.RuleStateEnabled || len(svc.tags) != 0 {
+		t.Errorf("the rule should be enabled and the tags should be removed: %s %v", svc.state, svc.tags)
+	}
+}
+
+func TestRule_resumeIfDue_disabled(t *testing.T) {
+	ctx := context.Background()
+	// rules disabled by the disable subcommand are not resumed
+	svc := &fakeRuleState{state: cweTypes.RuleStateDisabled, tags: map[string]string{disabledByTagKey: "alice"}}
+	ok, err := testRunRule().resumeIfDue(ctx, svc, time.Now(), false)
+	if err != nil || ok || svc.state != cweTypes.RuleStateDisabled {
+		t.Errorf("the rule should be left disabled: %v, %v", ok, err)
+	}
+}
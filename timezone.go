@@ -0,0 +1,391 @@
+package ecschedule
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/winebarrel/cronplan"
+)
+
+// convertScheduleTimezones applies scheduleTimezone of the rules. The scheduler backend takes it as
+// scheduleExpressionTimezone, and the cron expressions of the events backend, which are always in
+// UTC, are translated into UTC. The warnings of daylight saving time are kept in the rules and
+// logged by warnScheduleTimezones.
+func (c *Config) convertScheduleTimezones(now time.Time) error {
+	var errMsgs []string
+	for _, r := range c.Rules {
+		warnings, err := r.convertScheduleTimezone(now)
+		if err != nil {
+			errMsgs = append(errMsgs, fmt.Sprintf("\trule %q: %s", r.Name, err))
+			continue
+		}
+		r.scheduleTimezoneWarnings = warnings
+	}
+	if len(errMsgs) > 0 {
+		return fmt.Errorf("schedule timezone errors:\n%s", strings.Join(errMsgs, "\n"))
+	}
+	return nil
+}
+
+// warnScheduleTimezones logs the warnings of daylight saving time of the rules
+func (c *Config) warnScheduleTimezones(ruleNames []string) {
+	for _, name := range ruleNames {
+		r := c.GetRuleByName(name)
+		if r == nil {
+			continue
+		}
+		for _, w := range r.scheduleTimezoneWarnings {
+			log.Printf("⚠️  rule %q: %s", r.Name, w)
+		}
+	}
+}
+
+func (r *Rule) convertScheduleTimezone(now time.Time) ([]string, error) {
+	if r.BaseConfig == nil || r.ScheduleTimezone == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(r.ScheduleTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduleTimezone: %w", err)
+	}
+	if r.useScheduler() {
+		if r.ScheduleExpressionTimezone == "" {
+			r.ScheduleExpressionTimezone = r.ScheduleTimezone
+		}
+		return nil, nil
+	}
+	if !strings.HasPrefix(r.ScheduleExpression, "cron(") {
+		// rate expressions and event patterns don't depend on the timezone
+		return nil, nil
+	}
+	utc, warnings, err := cronToUTC(r.ScheduleExpression, loc, now)
+	if err != nil {
+		return nil, err
+	}
+	r.localScheduleExpression = r.ScheduleExpression
+	r.ScheduleExpression = utc
+	return warnings, nil
+}
+
+// carryScheduleTimezone takes over the cron expression in scheduleTimezone from the rule cr of
+// the configuration, when the rule r on AWS runs the expression translated from it.
+func (r *Rule) carryScheduleTimezone(cr *Rule) {
+	if cr == nil || cr.localScheduleExpression == "" || cr.ScheduleExpression != r.ScheduleExpression {
+		return
+	}
+	base := *r.BaseConfig
+	base.ScheduleTimezone = cr.ScheduleTimezone
+	r.BaseConfig = &base
+	r.localScheduleExpression = cr.localScheduleExpression
+}
+
+// annotateScheduleTimezone shows the cron expression in scheduleTimezone above the translated one
+// in the YAML of the rule. The remote one is annotated as well when it is the same expression.
+func (r *Rule) annotateScheduleTimezone(from, to string) (string, string) {
+	if r.localScheduleExpression == "" {
+		return from, to
+	}
+	line := fmt.Sprintf("\nscheduleExpression: %s\n", r.ScheduleExpression)
+	annotated := fmt.Sprintf("\n# %s in %s\nscheduleExpression: %s\n", r.localScheduleExpression, r.ScheduleTimezone, r.ScheduleExpression)
+	return strings.Replace(from, line, annotated, 1), strings.Replace(to, line, annotated, 1)
+}
+
+type zoneTransition struct {
+	at       time.Time
+	from, to int // offsets in seconds
+}
+
+// zoneTransitions returns the offsets of loc in the year from now in ascending order and the
+// transitions between them.
+func zoneTransitions(loc *time.Location, now time.Time) ([]int, []zoneTransition) {
+	var (
+		offsets     []int
+		transitions []zoneTransition
+		start       = now.UTC().Truncate(time.Hour)
+		_, prev     = start.In(loc).Zone()
+	)
+	offsets = append(offsets, prev)
+	for t := start.Add(15 * time.Minute); t.Before(start.AddDate(1, 0, 0)); t = t.Add(15 * time.Minute) {
+		_, off := t.In(loc).Zone()
+		if off == prev {
+			continue
+		}
+		// look for the exact instant of the transition
+		lo, hi := t.Add(-15*time.Minute), t
+		for hi.Sub(lo) > time.Second {
+			mid := lo.Add(hi.Sub(lo) / 2)
+			if _, o := mid.In(loc).Zone(); o == prev {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		transitions = append(transitions, zoneTransition{at: hi, from: prev, to: off})
+		if !slices.Contains(offsets, off) {
+			offsets = append(offsets, off)
+		}
+		prev = off
+	}
+	sort.Ints(offsets)
+	return offsets, transitions
+}
+
+func formatOffset(off int) string {
+	sign := "+"
+	if off < 0 {
+		sign, off = "-", -off
+	}
+	return fmt.Sprintf("UTC%s%02d:%02d", sign, off/3600, off%3600/60)
+}
+
+// cronToUTC translates the cron expression in loc into the equivalent one in UTC. The hours and
+// the minutes are shifted by the offset of loc, and so are the days of the month or the week when
+// the times cross midnight in UTC. When loc observes daylight saving time, the standard offset is
+// used and a warning is returned, unless the translation doesn't depend on the offset. Local times
+// which are skipped or repeated by the transitions are errors, since they are ambiguous.
+func cronToUTC(expr string, loc *time.Location, now time.Time) (string, []string, error) {
+	exp, err := cronplan.Parse(strings.TrimSuffix(strings.TrimPrefix(expr, "cron("), ")"))
+	if err != nil {
+		return "", nil, err
+	}
+	offsets, transitions := zoneTransitions(loc, now)
+	utc, err := shiftCron(exp, offsets[0])
+	if err != nil {
+		return "", nil, err
+	}
+	var exact = true
+	for _, off := range offsets[1:] {
+		if other, err := shiftCron(exp, off); err != nil || other != utc {
+			exact = false
+		}
+	}
+	if exact {
+		return utc, nil, nil
+	}
+
+	for _, tr := range transitions {
+		lo, hi := tr.from, tr.to
+		problem := "is skipped"
+		if lo > hi {
+			lo, hi = hi, lo
+			problem = "occurs twice"
+		}
+		// wall clock times around the transition expressed in UTC
+		for w := tr.at.UTC().Add(time.Duration(lo) * time.Second); w.Before(tr.at.UTC().Add(time.Duration(hi) * time.Second)); w = w.Add(time.Minute) {
+			if exp.Match(w) {
+				return "", nil, fmt.Errorf("%s %s in %s on the transition of daylight saving time, so %s can't be translated into UTC",
+					w.Format("2006-01-02 15:04"), problem, loc, expr)
+			}
+		}
+	}
+	var warnings []string
+	for _, off := range offsets[1:] {
+		diff := (off - offsets[0]) / 60
+		later := fmt.Sprintf("%d %s", diff, plural(diff, "minute"))
+		if diff%60 == 0 {
+			later = fmt.Sprintf("%d %s", diff/60, plural(diff/60, "hour"))
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"%s observes daylight saving time and %s is translated into %s with the standard offset %s, so it runs %s later in local time while the offset is %s",
+			loc, expr, utc, formatOffset(offsets[0]), later, formatOffset(off)))
+	}
+	return utc, warnings, nil
+}
+
+var cronWeekdays = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// shiftCron returns the cron expression which fires at the same instants as exp in the fixed
+// offset off (in seconds) but in UTC.
+func shiftCron(exp *cronplan.Expression, off int) (string, error) {
+	if off%60 != 0 {
+		return "", fmt.Errorf("offset %s is not in minutes", formatOffset(off))
+	}
+	var hours, minutes []int
+	for h := 0; h < 24; h++ {
+		if exp.Hour.Match(time.Date(2000, time.January, 1, h, 0, 0, 0, time.UTC)) {
+			hours = append(hours, h)
+		}
+	}
+	for m := 0; m < 60; m++ {
+		if exp.Minute.Match(time.Date(2000, time.January, 1, 0, m, 0, 0, time.UTC)) {
+			minutes = append(minutes, m)
+		}
+	}
+
+	var (
+		offMin  = off / 60
+		pairs   = map[[2]int]bool{}
+		uHours  = map[int]bool{}
+		uMins   = map[int]bool{}
+		carries = map[int]bool{}
+	)
+	for _, h := range hours {
+		for _, m := range minutes {
+			t := h*60 + m - offMin
+			carry := 0
+			switch {
+			case t < 0:
+				t, carry = t+24*60, -1
+			case t >= 24*60:
+				t, carry = t-24*60, 1
+			}
+			pairs[[2]int{t / 60, t % 60}] = true
+			uHours[t/60] = true
+			uMins[t%60] = true
+			carries[carry] = true
+		}
+	}
+	if len(uHours)*len(uMins) != len(pairs) {
+		return "", fmt.Errorf("the times shifted by %s can't be expressed with the hours and the minutes of a cron expression", formatOffset(off))
+	}
+
+	minute := exp.Minute.String()
+	if !slices.Equal(sortedInts(uMins), minutes) {
+		minute = joinInts(sortedInts(uMins))
+	}
+	hour := exp.Hour.String()
+	switch {
+	case len(uHours) == 24:
+		hour = "*"
+	case !slices.Equal(sortedInts(uHours), hours):
+		hour = joinInts(sortedInts(uHours))
+	}
+	dom, dow := exp.DayOfMonth.String(), exp.DayOfWeek.String()
+
+	everyDay := everyDayOfMonth(exp.DayOfMonth) && everyDayOfWeek(exp.DayOfWeek)
+	everyMonth := len(exp.Month.Exps) == 1 && exp.Month.Exps[0].Wildcard && exp.Month.Exps[0].Bottom == nil
+	everyYear := len(exp.Year.Exps) == 1 && exp.Year.Exps[0].Wildcard && exp.Year.Exps[0].Bottom == nil
+	switch {
+	case len(carries) > 1:
+		if !everyDay || !everyMonth || !everyYear {
+			return "", fmt.Errorf("some of the times cross midnight in UTC with the offset %s and others don't, so the days can't be shifted", formatOffset(off))
+		}
+	case carries[-1], carries[1]:
+		carry := 1
+		if carries[-1] {
+			carry = -1
+		}
+		var err error
+		if !exp.DayOfMonth.Any && !everyDayOfMonth(exp.DayOfMonth) {
+			dom, err = shiftDaysOfMonth(exp.DayOfMonth, carry, everyMonth && everyYear)
+		} else if !everyMonth || !everyYear {
+			err = errors.New("the days can't be shifted across the months or the years")
+		} else if !exp.DayOfWeek.Any && !everyDayOfWeek(exp.DayOfWeek) {
+			dow, err = shiftDaysOfWeek(exp.DayOfWeek, carry)
+		}
+		if err != nil {
+			return "", fmt.Errorf("the times cross midnight in UTC with the offset %s and %w", formatOffset(off), err)
+		}
+	}
+	return fmt.Sprintf("cron(%s %s %s %s %s %s)", minute, hour, dom, exp.Month, dow, exp.Year), nil
+}
+
+func everyDayOfMonth(f *cronplan.DayOfMonthField) bool {
+	return f.Any || len(f.Exps) == 1 && f.Exps[0].Wildcard && f.Exps[0].Bottom == nil
+}
+
+func everyDayOfWeek(f *cronplan.DayOfWeekField) bool {
+	return f.Any || len(f.Exps) == 1 && f.Exps[0].Wildcard && f.Exps[0].Bottom == nil
+}
+
+// shiftDaysOfMonth shifts the days by carry. The 1st and the last day move to the last day and the
+// 1st of the adjacent months when the expression runs in every month and year. The other days
+// which would move to another month, or which some months don't have, are errors, since the
+// months have different numbers of days.
+func shiftDaysOfMonth(f *cronplan.DayOfMonthField, carry int, everyMonth bool) (string, error) {
+	var (
+		days  []int
+		last  []string
+		first bool
+	)
+	for _, e := range f.Exps {
+		switch {
+		case e.NearestWeekday != nil, e.LastWeekday != nil:
+			return "", fmt.Errorf("%s can't be shifted", e)
+		case e.Last != nil:
+			n := e.Last.Int() - carry
+			switch {
+			case n < 0:
+				if !everyMonth {
+					return "", fmt.Errorf("%s would move to the next month", e)
+				}
+				first = true
+			case n > 27:
+				// L-27 is the 1st in February of common years
+				return "", fmt.Errorf("%s would move to another month in some months", e)
+			case n == 0:
+				last = append(last, "L")
+			default:
+				last = append(last, "L-"+strconv.Itoa(n))
+			}
+		default:
+			for d := 1; d <= 31; d++ {
+				if e.Match(time.Date(2000, time.January, d, 0, 0, 0, 0, time.UTC)) {
+					days = append(days, d)
+				}
+			}
+		}
+	}
+	var ss []string
+	if first {
+		ss = append(ss, "1")
+	}
+	for _, d := range slices.Compact(slices.Sorted(slices.Values(days))) {
+		switch {
+		case d > 28:
+			// the 28th is the last day which every month has
+			return "", fmt.Errorf("day %d doesn't exist in some months, so it can't be shifted", d)
+		case d+carry < 1 && everyMonth:
+			last = append([]string{"L"}, last...)
+		case d+carry < 1 || d+carry > 28:
+			return "", fmt.Errorf("day %d would move to another month in some months", d)
+		default:
+			ss = append(ss, strconv.Itoa(d+carry))
+		}
+	}
+	return strings.Join(append(ss, last...), ","), nil
+}
+
+// shiftDaysOfWeek shifts the weekdays by carry
+func shiftDaysOfWeek(f *cronplan.DayOfWeekField, carry int) (string, error) {
+	var wdays []int
+	for _, e := range f.Exps {
+		if e.Nth != nil || e.Last != nil && e.Last.Wday != nil {
+			return "", fmt.Errorf("%s can't be shifted", e)
+		}
+		// 2000-01-02 is Sunday
+		for i := 0; i < 7; i++ {
+			if e.Match(time.Date(2000, time.January, 2+i, 0, 0, 0, 0, time.UTC)) {
+				wdays = append(wdays, (i+carry+7)%7)
+			}
+		}
+	}
+	var ss []string
+	for _, w := range slices.Compact(slices.Sorted(slices.Values(wdays))) {
+		ss = append(ss, cronWeekdays[w])
+	}
+	return strings.Join(ss, ","), nil
+}
+
+func sortedInts(m map[int]bool) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func joinInts(ns []int) string {
+	ss := make([]string, len(ns))
+	for i, n := range ns {
+		ss[i] = strconv.Itoa(n)
+	}
+	return strings.Join(ss, ",")
+}
@@ -0,0 +1,215 @@
+package ecschedule
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCronToUTC(t *testing.T) {
+	testCases := []struct {
+		expr     string
+		timezone string
+		want     string
+	}{
+		{"cron(0 9 * * ? *)", "Asia/Tokyo", "cron(0 0 * * ? *)"},
+		{"cron(30 8 * * ? *)", "Asia/Tokyo", "cron(30 23 * * ? *)"},
+		{"cron(0 8 ? * MON-FRI *)", "Asia/Tokyo", "cron(0 23 ? * SUN,MON,TUE,WED,THU *)"},
+		{"cron(0 8,20 * * ? *)", "Asia/Tokyo", "cron(0 11,23 * * ? *)"},
+		{"cron(0 3 2,15 * ? *)", "Asia/Tokyo", "cron(0 18 1,14 * ? *)"},
+		{"cron(0 3 L * ? *)", "Asia/Tokyo", "cron(0 18 L-1 * ? *)"},
+		{"cron(0 1 1 * ? *)", "Asia/Tokyo", "cron(0 16 L * ? *)"},
+		{"cron(0 1 1,15 * ? *)", "Asia/Tokyo", "cron(0 16 14,L * ? *)"},
+		{"cron(0 3 L-26 * ? *)", "Asia/Tokyo", "cron(0 18 L-27 * ? *)"},
+		{"cron(0 22 L * ? *)", "America/Sao_Paulo", "cron(0 1 1 * ? *)"},
+		{"cron(0 22 1,L-2 * ? *)", "America/Sao_Paulo", "cron(0 1 2,L-1 * ? *)"},
+		{"cron(0 10 15 JAN ? *)", "Asia/Tokyo", "cron(0 1 15 JAN ? *)"},
+		{"cron(0/15 * * * ? *)", "Asia/Tokyo", "cron(0/15 * * * ? *)"},
+		{"cron(0 * * * ? *)", "Asia/Kolkata", "cron(30 * * * ? *)"},
+		{"cron(0 9 * * ? *)", "Asia/Kolkata", "cron(30 3 * * ? *)"},
+		{"cron(0/15 * * * ? *)", "America/New_York", "cron(0/15 * * * ? *)"},
+		{"cron(0 20 ? * FRI *)", "America/New_York", "cron(0 1 ? * SAT *)"},
+	}
+	now := mustTime(t, "2026-01-01T00:00:00Z")
+	for _, tc := range testCases {
+		loc, err := time.LoadLocation(tc.timezone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _, err := cronToUTC(tc.expr, loc, now)
+		if err != nil {
+			t.Errorf("%s in %s: %s", tc.expr, tc.timezone, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s in %s: got %s, want %s", tc.expr, tc.timezone, got, tc.want)
+		}
+	}
+}
+
+func TestCronToUTC_error(t *testing.T) {
+	testCases := []struct {
+		expr     string
+		timezone string
+		want     string
+	}{
+		{"cron(0 3 1 JAN ? *)", "Asia/Tokyo", "day 1 would move to another month"},
+		{"cron(0 3 1 * ? 2027)", "Asia/Tokyo", "day 1 would move to another month"},
+		{"cron(0 1 29 * ? *)", "Asia/Tokyo", "day 29 doesn't exist in some months"},
+		{"cron(0 3 L-27 * ? *)", "Asia/Tokyo", "L-27 would move to another month"},
+		{"cron(0 22 28 * ? *)", "America/Sao_Paulo", "day 28 would move to another month"},
+		{"cron(0 22 L JAN ? *)", "America/Sao_Paulo", "L would move to the next month"},
+		{"cron(0 8,20 ? * MON *)", "Asia/Tokyo", "some of the times cross midnight"},
+		{"cron(0 8 * JAN ? *)", "Asia/Tokyo", "the days can't be shifted across the months"},
+		{"cron(0 3 ? * MON#1 *)", "Asia/Tokyo", "MON#1 can't be shifted"},
+		{"cron(0 3 15W * ? *)", "Asia/Tokyo", "15W can't be shifted"},
+		{"cron(0/45 9 * * ? *)", "Asia/Kolkata", "can't be expressed with the hours and the minutes"},
+		{"cron(30 2 * * ? *)", "America/New_York", "2026-03-08 02:30 is skipped in America/New_York"},
+		{"cron(30 1 * * ? *)", "America/New_York", "2026-11-01 01:30 occurs twice in America/New_York"},
+	}
+	now := mustTime(t, "2026-01-01T00:00:00Z")
+	for _, tc := range testCases {
+		loc, err := time.LoadLocation(tc.timezone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, _, err := cronToUTC(tc.expr, loc, now)
+		if err == nil {
+			t.Errorf("%s in %s: error expected, got %s", tc.expr, tc.timezone, got)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s in %s: got %q, want %q", tc.expr, tc.timezone, err, tc.want)
+		}
+	}
+}
+
+func TestCronToUTC_dst(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, warnings, err := cronToUTC("cron(0 9 ? * MON-FRI *)", loc, mustTime(t, "2026-01-01T00:00:00Z"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "cron(0 14 ? * MON-FRI *)"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	want := "America/New_York observes daylight saving time and cron(0 9 ? * MON-FRI *) is translated into cron(0 14 ? * MON-FRI *) " +
+		"with the standard offset UTC-05:00, so it runs 1 hour later in local time while the offset is UTC-04:00"
+	if len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("unexpected warnings: %q", warnings)
+	}
+}
+
+// the translated expressions fire at the same instants as the ones in the timezone
+func TestCronToUTC_fireTimes(t *testing.T) {
+	testCases := []struct {
+		expr     string
+		timezone string
+	}{
+		{"cron(0 8 ? * MON-FRI *)", "Asia/Tokyo"},
+		{"cron(15 1,5 ? * SAT,SUN *)", "Asia/Tokyo"},
+		{"cron(0 3 L * ? *)", "Asia/Tokyo"},
+		{"cron(0 4 L-3 * ? *)", "Asia/Tokyo"},
+		{"cron(0 1 1,15 * ? *)", "Asia/Tokyo"},
+		{"cron(0 22 1,L * ? *)", "America/Sao_Paulo"},
+		{"cron(10 2 2-20/3 * ? *)", "Asia/Tokyo"},
+		{"cron(45 0/3 * * ? *)", "Asia/Kolkata"},
+		{"cron(0 21 ? * FRI *)", "America/Sao_Paulo"},
+	}
+	var (
+		now  = mustTime(t, "2026-01-01T00:00:00Z")
+		from = now
+		to   = now.AddDate(1, 0, 0)
+	)
+	for _, tc := range testCases {
+		loc, err := time.LoadLocation(tc.timezone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		utc, _, err := cronToUTC(tc.expr, loc, now)
+		if err != nil {
+			t.Fatalf("%s in %s: %s", tc.expr, tc.timezone, err)
+		}
+		local, err := parseSchedule(tc.expr, tc.timezone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		translated, err := parseSchedule(utc, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want []string
+		for _, ft := range fireTimesBetween(local, from, to) {
+			want = append(want, ft.UTC().Format(time.RFC3339))
+		}
+		got := formatTimes(fireTimesBetween(translated, from, to))
+		if len(want) == 0 || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s in %s: %s fires at different times", tc.expr, tc.timezone, utc)
+		}
+	}
+}
+
+func TestLoadConfig_scheduleTimezone(t *testing.T) {
+	conf := `region: ap-northeast-1
+cluster: api
+scheduleTimezone: Asia/Tokyo
+rules:
+- name: events-rule
+  scheduleExpression: cron(0 8 ? * MON-FRI *)
+  taskDefinition: task1
+- name: rate-rule
+  scheduleExpression: rate(1 hour)
+  taskDefinition: task1
+- name: scheduler-rule
+  scheduleExpression: cron(0 8 ? * MON-FRI *)
+  taskDefinition: task1
+  backend: scheduler
+- name: utc-rule
+  scheduleExpression: cron(0 8 ? * MON-FRI *)
+  taskDefinition: task1
+  scheduleTimezone: UTC
+- name: dst-rule
+  scheduleExpression: cron(0 9 ? * MON-FRI *)
+  taskDefinition: task1
+  scheduleTimezone: America/New_York
+`
+	c, err := LoadConfig(context.Background(), strings.NewReader(conf), "334", "config.yaml", WithNow(mustTime(t, "2026-01-01T00:00:00Z")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name, expr, timezone string
+	}{
+		{"events-rule", "cron(0 23 ? * SUN,MON,TUE,WED,THU *)", ""},
+		{"rate-rule", "rate(1 hour)", ""},
+		{"scheduler-rule", "cron(0 8 ? * MON-FRI *)", "Asia/Tokyo"},
+		{"utc-rule", "cron(0 8 ? * MON-FRI *)", ""},
+		{"dst-rule", "cron(0 14 ? * MON-FRI *)", ""},
+	}
+	for _, tc := range testCases {
+		r := c.GetRuleByName(tc.name)
+		if r.ScheduleExpression != tc.expr || r.ScheduleExpressionTimezone != tc.timezone {
+			t.Errorf("%s: got %s %q, want %s %q", tc.name, r.ScheduleExpression, r.ScheduleExpressionTimezone, tc.expr, tc.timezone)
+		}
+	}
+	// the warnings are kept to be logged by apply and diff
+	if w := c.GetRuleByName("dst-rule").scheduleTimezoneWarnings; len(w) != 1 {
+		t.Errorf("a warning of daylight saving time expected, but: %q", w)
+	}
+	if w := c.GetRuleByName("events-rule").scheduleTimezoneWarnings; len(w) != 0 {
+		t.Errorf("no warnings expected, but: %q", w)
+	}
+
+	from, to := c.GetRuleByName("events-rule").annotateScheduleTimezone(
+		"name: events-rule\nscheduleExpression: cron(0 23 ? * SUN,MON,TUE,WED,THU *)\n",
+		"name: events-rule\nscheduleExpression: cron(0 23 ? * SUN,MON,TUE,WED,THU *)\n")
+	want := "name: events-rule\n# cron(0 8 ? * MON-FRI *) in Asia/Tokyo\nscheduleExpression: cron(0 23 ? * SUN,MON,TUE,WED,THU *)\n"
+	if from != want || to != want {
+		t.Errorf("unexpected annotation:\n%s\n%s", from, to)
+	}
+}